@@ -0,0 +1,270 @@
+package uniqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+)
+
+var (
+	// ErrNoChangeLog is returned when changes are requested from a queue
+	// created without WithChangeLog.
+	ErrNoChangeLog = errors.New("uniqueue: queue has no change log")
+	// ErrChangeLogTruncated is returned when changes are requested from a
+	// sequence number older than the change log retains.
+	ErrChangeLogTruncated = errors.New("uniqueue: changes since sequence number are no longer retained")
+)
+
+// ChangeKind identifies the mutation recorded by a Change.
+type ChangeKind int
+
+const (
+	// ChangePushBack adds Item at the tail.
+	ChangePushBack ChangeKind = iota
+	// ChangePushFront adds Item at the head.
+	ChangePushFront
+	// ChangePop removes Item from the head or tail.
+	ChangePop
+	// ChangeRemove removes Item from anywhere in the queue, by Remove or
+	// Retain.
+	ChangeRemove
+	// ChangeReplace substitutes Item with Other in place.
+	ChangeReplace
+	// ChangeMoveToFront moves Item to the head.
+	ChangeMoveToFront
+	// ChangeMoveToBack moves Item to the tail.
+	ChangeMoveToBack
+	// ChangeMoveBefore moves Item in front of Other.
+	ChangeMoveBefore
+	// ChangeMoveAfter moves Item behind Other.
+	ChangeMoveAfter
+	// ChangeClear removes all items.
+	ChangeClear
+)
+
+var changeKindNames = [...]string{
+	ChangePushBack:    "push_back",
+	ChangePushFront:   "push_front",
+	ChangePop:         "pop",
+	ChangeRemove:      "remove",
+	ChangeReplace:     "replace",
+	ChangeMoveToFront: "move_to_front",
+	ChangeMoveToBack:  "move_to_back",
+	ChangeMoveBefore:  "move_before",
+	ChangeMoveAfter:   "move_after",
+	ChangeClear:       "clear",
+}
+
+func (k ChangeKind) String() string {
+	if k >= 0 && int(k) < len(changeKindNames) {
+		return changeKindNames[k]
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// MarshalText encodes the kind as its name, such as "push_back".
+func (k ChangeKind) MarshalText() ([]byte, error) {
+	if k < 0 || int(k) >= len(changeKindNames) {
+		return nil, fmt.Errorf("uniqueue: unknown change kind %d", int(k))
+	}
+	return []byte(changeKindNames[k]), nil
+}
+
+// UnmarshalText decodes a kind encoded by MarshalText.
+func (k *ChangeKind) UnmarshalText(text []byte) error {
+	for i, name := range changeKindNames {
+		if name == string(text) {
+			*k = ChangeKind(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("uniqueue: unknown change kind %q", text)
+}
+
+// Change is one mutation of a queue. Applying the changes of a queue in
+// order to an empty queue reproduces it.
+type Change[T comparable] struct {
+	// Seq is the sequence number of the queue after the change.
+	Seq  uint64     `json:"seq"`
+	Kind ChangeKind `json:"kind"`
+	Item T          `json:"item"`
+	// Other is the replacement for ChangeReplace and the mark for
+	// ChangeMoveBefore and ChangeMoveAfter.
+	Other T `json:"other"`
+}
+
+// changeLog is a ring buffer of the most recent changes.
+type changeLog[T comparable] struct {
+	buf   []Change[T]
+	start int
+	n     int
+}
+
+func newChangeLog[T comparable](size int) *changeLog[T] {
+	if size == 0 {
+		return nil
+	}
+	return &changeLog[T]{buf: make([]Change[T], size)}
+}
+
+func (l *changeLog[T]) add(c Change[T]) {
+	if l.n < len(l.buf) {
+		l.buf[(l.start+l.n)%len(l.buf)] = c
+		l.n++
+		return
+	}
+	l.buf[l.start] = c
+	l.start = (l.start + 1) % len(l.buf)
+}
+
+// record advances the sequence number and logs the change.
+func (u *UniqueueUnsafe[T]) record(kind ChangeKind, item, other T) {
+	u.seq++
+	if u.changes != nil {
+		u.changes.add(Change[T]{Seq: u.seq, Kind: kind, Item: item, Other: other})
+	}
+}
+
+func (u *UniqueueUnsafe[T]) changeLogSize() int {
+	if u.changes == nil {
+		return 0
+	}
+	return len(u.changes.buf)
+}
+
+// Seq returns the sequence number of the queue, which is incremented by
+// every mutation. Rejected duplicates and lookups do not change it.
+// Time complexity: O(1)
+func (u *UniqueueUnsafe[T]) Seq() uint64 {
+	return u.seq
+}
+
+// EnqueueSeq returns the sequence number of the push that added item.
+// Returns false if the item is not in the queue.
+// Time complexity: O(1)
+func (u *UniqueueUnsafe[T]) EnqueueSeq(item T) (uint64, bool) {
+	e, ok := u.seen[item]
+	return e.seq, ok
+}
+
+// ChangesSince returns the logged changes with a sequence number greater
+// than since, in order. It returns ErrNoChangeLog if the queue was created
+// without WithChangeLog, and ErrChangeLogTruncated if some of those
+// changes have already been overwritten.
+// Time complexity: O(k) where k is the number of changes returned
+func (u *UniqueueUnsafe[T]) ChangesSince(since uint64) ([]Change[T], error) {
+	l := u.changes
+	if l == nil {
+		return nil, ErrNoChangeLog
+	}
+	if since >= u.seq {
+		return nil, nil
+	}
+	if l.n == 0 || since+1 < l.buf[l.start].Seq {
+		return nil, ErrChangeLogTruncated
+	}
+
+	skip := int(since + 1 - l.buf[l.start].Seq)
+	result := make([]Change[T], 0, l.n-skip)
+	for i := skip; i < l.n; i++ {
+		result = append(result, l.buf[(l.start+i)%len(l.buf)])
+	}
+	return result, nil
+}
+
+// Apply replays c on the queue. A change whose effect is already present,
+// such as pushing a queued item or removing a missing one, does nothing,
+// so applying a change twice is harmless. ChangePop removes c.Item
+// wherever it is. The queue records the change under its own sequence
+// number; c.Seq is ignored.
+// Time complexity: O(1), O(n) for ChangeClear with observers
+func (u *UniqueueUnsafe[T]) Apply(c Change[T]) {
+	switch c.Kind {
+	case ChangePushBack:
+		u.PushBack(c.Item)
+	case ChangePushFront:
+		u.PushFront(c.Item)
+	case ChangePop, ChangeRemove:
+		u.Remove(c.Item)
+	case ChangeReplace:
+		u.Replace(c.Item, c.Other)
+	case ChangeMoveToFront:
+		u.MoveToFront(c.Item)
+	case ChangeMoveToBack:
+		u.MoveToBack(c.Item)
+	case ChangeMoveBefore:
+		u.MoveBefore(c.Item, c.Other)
+	case ChangeMoveAfter:
+		u.MoveAfter(c.Item, c.Other)
+	case ChangeClear:
+		u.Clear()
+	}
+}
+
+// Seq returns the sequence number of the queue, which is incremented by
+// every mutation.
+// Time complexity: O(1)
+func (u *Uniqueue[T]) Seq() uint64 {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.uniqueue.Seq()
+}
+
+// EnqueueSeq returns the sequence number of the push that added item.
+// Returns false if the item is not in the queue.
+// Time complexity: O(1)
+func (u *Uniqueue[T]) EnqueueSeq(item T) (uint64, bool) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.uniqueue.EnqueueSeq(item)
+}
+
+// Changes returns an iterator over the changes with a sequence number
+// greater than since, which blocks for new changes once it has caught up.
+// since is typically 0 or a value returned by Seq. The iterator stops
+// after yielding a non-nil error: ctx.Err() once ctx is done,
+// ErrChangeLogTruncated if the consumer fell behind the change log, or
+// ErrNoChangeLog.
+func (u *Uniqueue[T]) Changes(ctx context.Context, since uint64) iter.Seq2[Change[T], error] {
+	return func(yield func(Change[T], error) bool) {
+		for {
+			var batch []Change[T]
+			var err error
+			waitErr := u.wait(ctx, func() bool {
+				batch, err = u.uniqueue.ChangesSince(since)
+				return err != nil || len(batch) > 0
+			})
+			if waitErr != nil {
+				err = waitErr
+			}
+			if err != nil {
+				yield(Change[T]{}, err)
+				return
+			}
+			for _, c := range batch {
+				if !yield(c, nil) {
+					return
+				}
+				since = c.Seq
+			}
+		}
+	}
+}
+
+// Apply replays c on the queue like UniqueueUnsafe.Apply. It is meant for
+// replicas: leases, debounce and throttle are not consulted.
+// Time complexity: O(1), O(n) for ChangeClear with observers
+func (u *Uniqueue[T]) Apply(c Change[T]) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	seq := u.uniqueue.Seq()
+	u.uniqueue.Apply(c)
+	if u.uniqueue.Seq() != seq {
+		u.forgetAttempts()
+		u.broadcast()
+	}
+}
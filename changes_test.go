@@ -0,0 +1,169 @@
+package uniqueue
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUniqueueUnsafe_Seq(t *testing.T) {
+	u := NewUniqueueUnsafe[string]()
+	if u.Seq() != 0 {
+		t.Errorf("Expected seq 0, got %d", u.Seq())
+	}
+
+	u.PushBack("a")
+	u.PushBack("b")
+	u.PushBack("a")
+	if u.Seq() != 2 {
+		t.Errorf("Expected duplicates not to change seq, got %d", u.Seq())
+	}
+	if seq, ok := u.EnqueueSeq("b"); !ok || seq != 2 {
+		t.Errorf("Expected (2, true), got (%d, %v)", seq, ok)
+	}
+
+	u.MoveToFront("b")
+	u.PopHead()
+	u.Remove("missing")
+	if u.Seq() != 4 {
+		t.Errorf("Expected seq 4, got %d", u.Seq())
+	}
+	if _, ok := u.EnqueueSeq("b"); ok {
+		t.Error("Expected no enqueue seq for a popped item")
+	}
+
+	if c := u.Clone(); c.Seq() != u.Seq() {
+		t.Errorf("Expected Clone to keep seq %d, got %d", u.Seq(), c.Seq())
+	}
+}
+
+func TestUniqueueUnsafe_ChangesSince(t *testing.T) {
+	u := NewUniqueueUnsafe[int](WithChangeLog(64))
+	for i := range 6 {
+		u.PushBack(i)
+	}
+	u.PushFront(9)
+	u.PopHead()
+	u.PopTail()
+	u.Remove(2)
+	u.Replace(3, 30)
+	u.MoveToFront(4)
+	u.MoveToBack(0)
+	u.MoveBefore(1, 30)
+	u.MoveAfter(4, 30)
+	u.Retain(func(v int) bool { return v != 1 })
+	other := newUniqueueUnsafeOf(7, 30)
+	u.Merge(other, MergeRelocateDuplicates)
+
+	changes, err := u.ChangesSince(0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(changes) != int(u.Seq()) {
+		t.Errorf("Expected %d changes, got %d", u.Seq(), len(changes))
+	}
+	replica := NewUniqueueUnsafe[int]()
+	for i, c := range changes {
+		if c.Seq != uint64(i+1) {
+			t.Errorf("Expected change %d to have seq %d, got %d", i, i+1, c.Seq)
+		}
+		replica.Apply(c)
+	}
+	if !replica.Equal(u) {
+		t.Errorf("Expected replaying changes to give %v, got %v", u.ToSlice(), replica.ToSlice())
+	}
+
+	tail, _ := u.ChangesSince(u.Seq() - 2)
+	if len(tail) != 2 || tail[1].Seq != u.Seq() {
+		t.Errorf("Expected the last 2 changes, got %v", tail)
+	}
+	if none, err := u.ChangesSince(u.Seq()); err != nil || len(none) != 0 {
+		t.Errorf("Expected no changes, got (%v, %v)", none, err)
+	}
+
+	u.Clear()
+	last, _ := u.ChangesSince(u.Seq() - 1)
+	if len(last) != 1 || last[0].Kind != ChangeClear {
+		t.Errorf("Expected a clear change, got %v", last)
+	}
+}
+
+func TestUniqueueUnsafe_ChangesSince_Errors(t *testing.T) {
+	if _, err := NewUniqueueUnsafe[int]().ChangesSince(0); !errors.Is(err, ErrNoChangeLog) {
+		t.Errorf("Expected ErrNoChangeLog, got %v", err)
+	}
+
+	u := NewUniqueueUnsafe[int](WithChangeLog(2))
+	u.PushBack(1)
+	u.PushBack(2)
+	u.PushBack(3)
+	if _, err := u.ChangesSince(0); !errors.Is(err, ErrChangeLogTruncated) {
+		t.Errorf("Expected ErrChangeLogTruncated, got %v", err)
+	}
+	changes, err := u.ChangesSince(1)
+	if err != nil || len(changes) != 2 || changes[0].Item != 2 || changes[1].Item != 3 {
+		t.Errorf("Expected changes for 2 and 3, got (%v, %v)", changes, err)
+	}
+
+	if _, err := NewUniqueueWithOptions[int](WithChangeLog(0)); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Expected ErrInvalidOption, got %v", err)
+	}
+}
+
+func TestUniqueue_Changes(t *testing.T) {
+	u := NewUniqueue[string](WithChangeLog(16))
+	u.PushBack("a")
+	since := u.Seq()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	got := make(chan Change[string])
+	done := make(chan error, 1)
+	go func() {
+		for c, err := range u.Changes(ctx, since) {
+			if err != nil {
+				done <- err
+				return
+			}
+			got <- c
+		}
+	}()
+
+	u.PushBack("b")
+	u.PopHead()
+	expected := []Change[string]{
+		{Seq: 2, Kind: ChangePushBack, Item: "b"},
+		{Seq: 3, Kind: ChangePop, Item: "a"},
+	}
+	for _, want := range expected {
+		select {
+		case c := <-got:
+			if !reflect.DeepEqual(c, want) {
+				t.Errorf("Expected %+v, got %+v", want, c)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for %+v", want)
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Changes did not stop after cancel")
+	}
+}
+
+func TestChangeKind_String(t *testing.T) {
+	if got := ChangeMoveBefore.String(); got != "move_before" {
+		t.Errorf("Expected move_before, got %s", got)
+	}
+	if got := ChangeKind(99).String(); got != "ChangeKind(99)" {
+		t.Errorf("Expected ChangeKind(99), got %s", got)
+	}
+}
@@ -0,0 +1,260 @@
+// Command uniqueue removes duplicate lines from its input while keeping
+// the order in which lines were first seen, like an order-preserving
+// "sort -u".
+//
+// Usage:
+//
+//	uniqueue [flags] [file ...]
+//
+// Lines are read from the named files, or from standard input if none are
+// given. By default the whole line is the deduplication key; -f and -r
+// select part of the line instead. The first line with a given key is
+// printed and later ones are suppressed.
+//
+// Memory grows with the number of distinct keys unless it is bounded with
+// -window (forget keys not seen in the last N lines) or -max-keys (forget
+// the least recently seen key when more than N are tracked). A forgotten
+// key is printed again the next time it appears.
+//
+// With -c each printed line is prefixed by the number of times its key was
+// seen, as "uniq -c" does. Counts are only known once a key is forgotten,
+// so in that mode a line is printed when its key leaves the window or at
+// the end of input.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/realfatcat/uniqueue"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// config holds the parsed command-line flags.
+type config struct {
+	field   int
+	delim   string
+	pattern *regexp.Regexp
+	window  int
+	maxKeys int
+	counts  bool
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("uniqueue", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: uniqueue [flags] [file ...]")
+		fs.PrintDefaults()
+	}
+
+	var (
+		cfg     config
+		pattern string
+	)
+	fs.IntVar(&cfg.field, "f", 0, "use the `N`th field (1-based) as the key")
+	fs.StringVar(&cfg.delim, "d", "", "field `delimiter` for -f (default: runs of whitespace)")
+	fs.StringVar(&pattern, "r", "", "use the first match of `regexp` as the key, or its first group if it has one")
+	fs.IntVar(&cfg.window, "window", 0, "only suppress keys seen within the last `N` lines (0: unlimited)")
+	fs.IntVar(&cfg.maxKeys, "max-keys", 0, "remember at most `N` keys, forgetting the least recently seen (0: unlimited)")
+	fs.BoolVar(&cfg.counts, "c", false, "prefix lines with the number of occurrences")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if cfg.field < 0 || cfg.window < 0 || cfg.maxKeys < 0 {
+		fmt.Fprintln(stderr, "uniqueue: -f, -window and -max-keys must not be negative")
+		return 2
+	}
+	if pattern != "" {
+		if cfg.field > 0 {
+			fmt.Fprintln(stderr, "uniqueue: -f and -r are mutually exclusive")
+			return 2
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			fmt.Fprintf(stderr, "uniqueue: invalid -r: %v\n", err)
+			return 2
+		}
+		cfg.pattern = re
+	}
+
+	out := bufio.NewWriter(stdout)
+	d := newDeduper(cfg, out)
+
+	status := 0
+	if fs.NArg() == 0 {
+		if err := d.read(stdin); err != nil {
+			fmt.Fprintf(stderr, "uniqueue: %v\n", err)
+			status = 1
+		}
+	}
+	for _, name := range fs.Args() {
+		if err := d.readFile(name); err != nil {
+			fmt.Fprintf(stderr, "uniqueue: %v\n", err)
+			status = 1
+		}
+	}
+	d.finish()
+
+	if err := out.Flush(); err != nil {
+		fmt.Fprintf(stderr, "uniqueue: %v\n", err)
+		return 1
+	}
+	return status
+}
+
+// sighting records what is known about a remembered key.
+type sighting struct {
+	text  string // first line with the key
+	first int    // line number of text
+	last  int    // line number of the latest occurrence
+	count int
+}
+
+// deduper suppresses repeated keys. Remembered keys are kept in a
+// UniqueueUnsafe ordered by their latest occurrence, so the key to forget
+// next, whether because it fell out of the window or because too many
+// keys are remembered, is always at the head.
+type deduper struct {
+	cfg    config
+	out    io.Writer
+	keys   *uniqueue.UniqueueUnsafe[string]
+	seen   map[string]*sighting
+	lineNo int
+}
+
+func newDeduper(cfg config, out io.Writer) *deduper {
+	return &deduper{
+		cfg:  cfg,
+		out:  out,
+		keys: uniqueue.NewUniqueueUnsafe[string](),
+		seen: make(map[string]*sighting),
+	}
+}
+
+func (d *deduper) readFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return d.read(f)
+}
+
+func (d *deduper) read(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		d.line(sc.Text())
+	}
+	return sc.Err()
+}
+
+func (d *deduper) line(text string) {
+	d.lineNo++
+	if d.cfg.window > 0 {
+		d.expire(d.lineNo - d.cfg.window - 1)
+	}
+
+	key := d.key(text)
+	if s, ok := d.seen[key]; ok {
+		s.last = d.lineNo
+		s.count++
+		d.keys.MoveToBack(key)
+		return
+	}
+
+	if !d.cfg.counts {
+		fmt.Fprintln(d.out, text)
+	}
+	d.keys.PushBack(key)
+	d.seen[key] = &sighting{text: text, first: d.lineNo, last: d.lineNo, count: 1}
+	if d.cfg.maxKeys > 0 && d.keys.Size() > d.cfg.maxKeys {
+		d.forgetOldest()
+	}
+}
+
+// expire forgets keys whose latest occurrence is at or before line.
+func (d *deduper) expire(line int) {
+	for {
+		key, ok := d.keys.Peek()
+		if !ok || d.seen[key].last > line {
+			return
+		}
+		d.forgetOldest()
+	}
+}
+
+func (d *deduper) forgetOldest() {
+	key, ok := d.keys.PopHead()
+	if !ok {
+		return
+	}
+	s := d.seen[key]
+	delete(d.seen, key)
+	if d.cfg.counts {
+		d.printCount(s)
+	}
+}
+
+// finish prints the counts of all remembered keys in first-seen order.
+func (d *deduper) finish() {
+	if !d.cfg.counts {
+		return
+	}
+	remaining := make([]*sighting, 0, len(d.seen))
+	for _, s := range d.seen {
+		remaining = append(remaining, s)
+	}
+	sort.Slice(remaining, func(i, j int) bool {
+		return remaining[i].first < remaining[j].first
+	})
+	for _, s := range remaining {
+		d.printCount(s)
+	}
+}
+
+func (d *deduper) printCount(s *sighting) {
+	fmt.Fprintf(d.out, "%7d %s\n", s.count, s.text)
+}
+
+// key extracts the deduplication key from a line. Lines without the
+// requested field or match use the whole line as key.
+func (d *deduper) key(text string) string {
+	switch {
+	case d.cfg.pattern != nil:
+		m := d.cfg.pattern.FindStringSubmatch(text)
+		switch {
+		case m == nil:
+			return text
+		case len(m) > 1:
+			return m[1]
+		default:
+			return m[0]
+		}
+	case d.cfg.field > 0:
+		var fields []string
+		if d.cfg.delim == "" {
+			fields = strings.Fields(text)
+		} else {
+			fields = strings.Split(text, d.cfg.delim)
+		}
+		if d.cfg.field > len(fields) {
+			return text
+		}
+		return fields[d.cfg.field-1]
+	default:
+		return text
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runWith(t *testing.T, input string, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr strings.Builder
+	code := run(args, strings.NewReader(input), &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		expected string
+	}{
+		{
+			name:     "first-seen order",
+			input:    "b\na\nb\nc\na\n",
+			expected: "b\na\nc\n",
+		},
+		{
+			name:     "field key",
+			args:     []string{"-f", "2"},
+			input:    "1 x\n2 y\n3 x\n4 z\n",
+			expected: "1 x\n2 y\n4 z\n",
+		},
+		{
+			name:     "field with delimiter",
+			args:     []string{"-f", "1", "-d", ","},
+			input:    "a,1\nb,2\na,3\n",
+			expected: "a,1\nb,2\n",
+		},
+		{
+			name:     "missing field uses whole line",
+			args:     []string{"-f", "3"},
+			input:    "a b\na b\na b c\n",
+			expected: "a b\na b c\n",
+		},
+		{
+			name:     "regexp group",
+			args:     []string{"-r", `id=(\d+)`},
+			input:    "x id=1\ny id=2\nz id=1\n",
+			expected: "x id=1\ny id=2\n",
+		},
+		{
+			name:     "regexp without group",
+			args:     []string{"-r", `[a-z]+`},
+			input:    "foo1\nfoo2\nbar3\n",
+			expected: "foo1\nbar3\n",
+		},
+		{
+			name:     "counts",
+			args:     []string{"-c"},
+			input:    "b\na\nb\nb\n",
+			expected: "      3 b\n      1 a\n",
+		},
+		{
+			name:     "window",
+			args:     []string{"-window", "2"},
+			input:    "a\nb\na\nc\nd\na\n",
+			expected: "a\nb\nc\nd\na\n",
+		},
+		{
+			name:     "window refreshed by repeats",
+			args:     []string{"-window", "1"},
+			input:    "a\na\na\nb\na\n",
+			expected: "a\nb\na\n",
+		},
+		{
+			name:     "max keys",
+			args:     []string{"-max-keys", "2"},
+			input:    "a\nb\na\nc\nb\na\n",
+			expected: "a\nb\nc\nb\na\n",
+		},
+		{
+			name:     "counts with bounded memory",
+			args:     []string{"-c", "-max-keys", "1"},
+			input:    "a\na\nb\na\n",
+			expected: "      2 a\n      1 b\n      1 a\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, errOut, code := runWith(t, tt.input, tt.args...)
+			if code != 0 {
+				t.Fatalf("Expected exit code 0, got %d (stderr: %s)", code, errOut)
+			}
+			if out != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, out)
+			}
+		})
+	}
+}
+
+func TestRun_Files(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first")
+	second := filepath.Join(dir, "second")
+	os.WriteFile(first, []byte("a\nb\n"), 0o644)
+	os.WriteFile(second, []byte("b\nc\n"), 0o644)
+
+	out, _, code := runWith(t, "ignored\n", first, second)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	if out != "a\nb\nc\n" {
+		t.Errorf("Expected deduplication across files, got %q", out)
+	}
+
+	out, errOut, code := runWith(t, "", first, filepath.Join(dir, "missing"))
+	if code != 1 {
+		t.Errorf("Expected exit code 1 for a missing file, got %d", code)
+	}
+	if out != "a\nb\n" || !strings.Contains(errOut, "missing") {
+		t.Errorf("Expected readable files to be processed and the error reported, got %q / %q", out, errOut)
+	}
+}
+
+func TestRun_InvalidFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"-f", "1", "-r", "x"},
+		{"-r", "("},
+		{"-window", "-1"},
+		{"-unknown"},
+	} {
+		if _, errOut, code := runWith(t, "", args...); code != 2 || errOut == "" {
+			t.Errorf("%v: expected exit code 2 with a message, got %d (%q)", args, code, errOut)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package uniqueue
+
+// CoalescingQueue is a FIFO queue of keys, each carrying the latest value
+// pushed for it. Pushing a key that is already pending replaces or merges
+// its value but keeps its position, so a burst of updates to one key is
+// delivered once, with the latest state.
+// Like UniqueueUnsafe, it should only be used from a single goroutine.
+type CoalescingQueue[K comparable, V any] struct {
+	order  *UniqueueUnsafe[K]
+	values map[K]V
+}
+
+// NewCoalescingQueue creates and returns a new empty coalescing queue.
+// opts configure the underlying key ordering; observers and metrics see
+// the keys, and a coalesced update is reported as a duplicate.
+// It panics if the options are invalid; use
+// NewCoalescingQueueWithOptions to get an error instead.
+func NewCoalescingQueue[K comparable, V any](opts ...Option) *CoalescingQueue[K, V] {
+	q, err := NewCoalescingQueueWithOptions[K, V](opts...)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// NewCoalescingQueueWithOptions is like NewCoalescingQueue but returns an
+// error wrapping ErrInvalidOption if the options are invalid.
+func NewCoalescingQueueWithOptions[K comparable, V any](opts ...Option) (*CoalescingQueue[K, V], error) {
+	order, err := NewUniqueueUnsafeWithOptions[K](opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &CoalescingQueue[K, V]{
+		order:  order,
+		values: make(map[K]V, order.sizeHint),
+	}, nil
+}
+
+// Upsert sets the pending value of k to v. If k is not pending it is added
+// to the end of the queue. Returns true if k was added, false if an
+// existing value was replaced.
+// Time complexity: O(1)
+func (q *CoalescingQueue[K, V]) Upsert(k K, v V) bool {
+	return q.UpsertWith(k, v, nil)
+}
+
+// UpsertWith is like Upsert, but if k is already pending its value becomes
+// merge(old, v). A nil merge replaces the value.
+// Time complexity: O(1) plus the cost of merge
+func (q *CoalescingQueue[K, V]) UpsertWith(k K, v V, merge func(old, new V) V) bool {
+	old, pending := q.values[k]
+	if pending && merge != nil {
+		v = merge(old, v)
+	}
+	q.values[k] = v
+	q.order.PushBack(k)
+	return !pending
+}
+
+// Pop removes and returns the first key and its latest value.
+// Returns zero values and false if the queue is empty.
+// Time complexity: O(1)
+func (q *CoalescingQueue[K, V]) Pop() (K, V, bool) {
+	peak := q.order.peak
+	k, ok := q.order.PopHead()
+	if !ok {
+		var v V
+		return k, v, false
+	}
+	v := q.values[k]
+	delete(q.values, k)
+	q.followCompaction(peak)
+	return k, v, true
+}
+
+// Peek returns the first key and its value without removing them.
+// Returns zero values and false if the queue is empty.
+// Time complexity: O(1)
+func (q *CoalescingQueue[K, V]) Peek() (K, V, bool) {
+	k, ok := q.order.Peek()
+	return k, q.values[k], ok
+}
+
+// Get returns the pending value of k.
+// Time complexity: O(1)
+func (q *CoalescingQueue[K, V]) Get(k K) (V, bool) {
+	v, ok := q.values[k]
+	return v, ok
+}
+
+// Remove deletes k and its value wherever it is in the queue.
+// Returns false if k is not pending.
+// Time complexity: O(1)
+func (q *CoalescingQueue[K, V]) Remove(k K) bool {
+	peak := q.order.peak
+	if !q.order.Remove(k) {
+		return false
+	}
+	delete(q.values, k)
+	q.followCompaction(peak)
+	return true
+}
+
+// Contains reports whether k is pending.
+// Time complexity: O(1)
+func (q *CoalescingQueue[K, V]) Contains(k K) bool {
+	return q.order.Contains(k)
+}
+
+// Size returns the number of pending keys.
+// Time complexity: O(1)
+func (q *CoalescingQueue[K, V]) Size() int {
+	return q.order.Size()
+}
+
+// IsEmpty returns true if no key is pending, false otherwise.
+// Time complexity: O(1)
+func (q *CoalescingQueue[K, V]) IsEmpty() bool {
+	return q.order.IsEmpty()
+}
+
+// Clear removes all keys and values.
+// Time complexity: O(n) with observers, O(1) otherwise
+func (q *CoalescingQueue[K, V]) Clear() {
+	q.order.Clear()
+	q.values = make(map[K]V, q.order.sizeHint)
+}
+
+// Compact rebuilds the key index and the value map at their current size,
+// releasing the memory Go maps keep after keys are removed. Keys are also
+// compacted automatically if WithAutoCompact is set.
+// Time complexity: O(n)
+func (q *CoalescingQueue[K, V]) Compact() {
+	q.order.Compact()
+	q.compactValues()
+}
+
+// followCompaction rebuilds the value map if the key ordering compacted
+// itself, which resets its peak below the one seen before the removal.
+func (q *CoalescingQueue[K, V]) followCompaction(peak int) {
+	if q.order.peak < peak {
+		q.compactValues()
+	}
+}
+
+func (q *CoalescingQueue[K, V]) compactValues() {
+	values := make(map[K]V, max(len(q.values), q.order.sizeHint))
+	for k, v := range q.values {
+		values[k] = v
+	}
+	q.values = values
+}
+
+// Stats returns the lifetime counters of the key ordering; Duplicates
+// counts coalesced updates.
+func (q *CoalescingQueue[K, V]) Stats() Stats {
+	return q.order.Stats()
+}
@@ -0,0 +1,130 @@
+package uniqueue
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCoalescingQueue_Upsert(t *testing.T) {
+	q := NewCoalescingQueue[string, int]()
+	if !q.Upsert("a", 1) || !q.Upsert("b", 2) {
+		t.Error("Expected new keys to be added")
+	}
+	if q.Upsert("a", 3) {
+		t.Error("Expected an upsert of a pending key to report a replacement")
+	}
+	if q.Size() != 2 {
+		t.Errorf("Expected size 2, got %d", q.Size())
+	}
+	if v, ok := q.Get("a"); !ok || v != 3 {
+		t.Errorf("Expected (3, true), got (%d, %v)", v, ok)
+	}
+
+	// a keeps its original position but carries the latest value.
+	if k, v, ok := q.Pop(); !ok || k != "a" || v != 3 {
+		t.Errorf("Expected (a, 3, true), got (%s, %d, %v)", k, v, ok)
+	}
+	if k, v, ok := q.Pop(); !ok || k != "b" || v != 2 {
+		t.Errorf("Expected (b, 2, true), got (%s, %d, %v)", k, v, ok)
+	}
+	if _, _, ok := q.Pop(); ok {
+		t.Error("Expected Pop on empty queue to return false")
+	}
+
+	// A popped key starts over at the tail.
+	q.Upsert("b", 4)
+	q.Upsert("a", 5)
+	if k, _, _ := q.Peek(); k != "b" {
+		t.Errorf("Expected b at the head, got %s", k)
+	}
+
+	expected := Stats{Adds: 4, Duplicates: 1, Pops: 2}
+	if got := q.Stats(); got != expected {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestCoalescingQueue_UpsertWith(t *testing.T) {
+	q := NewCoalescingQueue[string, []string]()
+	appendEvents := func(old, new []string) []string { return append(old, new...) }
+
+	q.UpsertWith("cfg", []string{"x=1"}, appendEvents)
+	q.UpsertWith("other", []string{"y=1"}, appendEvents)
+	q.UpsertWith("cfg", []string{"x=2"}, appendEvents)
+	q.UpsertWith("cfg", []string{"z=1"}, nil)
+	q.UpsertWith("cfg", []string{"z=2"}, appendEvents)
+
+	k, v, _ := q.Pop()
+	if k != "cfg" || !reflect.DeepEqual(v, []string{"z=1", "z=2"}) {
+		t.Errorf("Expected (cfg, [z=1 z=2]), got (%s, %v)", k, v)
+	}
+}
+
+func TestCoalescingQueue_RemoveClear(t *testing.T) {
+	obs := &recordingObserver{}
+	q := NewCoalescingQueue[int, string](WithObserver[int](obs))
+	q.Upsert(1, "a")
+	q.Upsert(2, "b")
+	q.Upsert(3, "c")
+
+	if !q.Remove(2) || q.Remove(2) {
+		t.Error("Expected Remove to succeed once")
+	}
+	if _, ok := q.Get(2); ok || q.Contains(2) {
+		t.Error("Expected removed key to be forgotten")
+	}
+
+	q.Clear()
+	if !q.IsEmpty() {
+		t.Errorf("Expected empty queue, got size %d", q.Size())
+	}
+	if _, ok := q.Get(1); ok {
+		t.Error("Expected Clear to drop values")
+	}
+
+	expected := []string{"push:1", "push:2", "push:3", "remove:2", "drop:1", "drop:3"}
+	if got := obs.Events(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestNewCoalescingQueueWithOptions_Invalid(t *testing.T) {
+	if _, err := NewCoalescingQueueWithOptions[int, int](WithMaxDeliveries(1)); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Expected ErrInvalidOption, got %v", err)
+	}
+}
+
+func TestCoalescingQueue_AutoCompact(t *testing.T) {
+	q := NewCoalescingQueue[int, int](WithAutoCompact(16))
+	for i := range 100 {
+		q.Upsert(i, i)
+	}
+	values := reflect.ValueOf(q.values).UnsafePointer()
+
+	for range 74 {
+		q.Pop()
+	}
+	if reflect.ValueOf(q.values).UnsafePointer() != values {
+		t.Error("Expected the values to be kept above a quarter of the peak")
+	}
+	q.Remove(74)
+	if reflect.ValueOf(q.values).UnsafePointer() == values {
+		t.Error("Expected the values to be rebuilt along with the key index")
+	}
+	if k, v, ok := q.Pop(); !ok || k != 75 || v != 75 {
+		t.Errorf("Expected (75, 75, true), got (%d, %d, %v)", k, v, ok)
+	}
+
+	values = reflect.ValueOf(q.values).UnsafePointer()
+	q.Compact()
+	if reflect.ValueOf(q.values).UnsafePointer() == values {
+		t.Error("Expected Compact to rebuild the values")
+	}
+	if q.Size() != 24 || len(q.values) != 24 {
+		t.Errorf("Expected 24 keys and values, got %d and %d", q.Size(), len(q.values))
+	}
+	if v, ok := q.Get(99); !ok || v != 99 {
+		t.Errorf("Expected (99, true), got (%d, %v)", v, ok)
+	}
+}
@@ -0,0 +1,38 @@
+package uniqueue
+
+// Compact rebuilds the queue's index at its current size, or at the size
+// hint if that is larger, releasing the memory Go maps keep after items
+// are removed. Items, order and enqueue timestamps are unchanged.
+// Time complexity: O(n)
+func (u *UniqueueUnsafe[T]) Compact() {
+	seen := make(map[T]entry[T], max(len(u.seen), u.sizeHint))
+	for item, e := range u.seen {
+		seen[item] = e
+	}
+	u.seen = seen
+	u.peak = len(seen)
+}
+
+// maybeCompact compacts the index once the queue has drained to a quarter
+// of a peak of at least autoCompact items. Since the peak is reset by each
+// rebuild, a rebuild of n items follows at least 3n removals.
+func (u *UniqueueUnsafe[T]) maybeCompact() {
+	if u.autoCompact == 0 || u.peak < u.autoCompact || u.peak <= u.sizeHint {
+		return
+	}
+	if len(u.seen) <= u.peak/4 {
+		u.Compact()
+	}
+}
+
+// Compact rebuilds the queue's indexes at their current size, releasing
+// the memory Go maps keep after items are removed. See
+// UniqueueUnsafe.Compact.
+// Time complexity: O(n)
+func (u *Uniqueue[T]) Compact() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.uniqueue.Compact()
+	u.leases.compact()
+}
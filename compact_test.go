@@ -0,0 +1,103 @@
+package uniqueue
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUniqueueUnsafe_Compact(t *testing.T) {
+	clock := newFakeClock()
+	u := NewUniqueueUnsafe[int](withClock(clock.Now), WithSizeHint(4))
+	for i := range 100 {
+		u.PushBack(i)
+		clock.Advance(time.Second)
+	}
+	for range 97 {
+		u.PopHead()
+	}
+	age := u.OldestAge()
+
+	u.Compact()
+	assertOrder(t, u, []int{97, 98, 99})
+	if got := u.OldestAge(); got != age {
+		t.Errorf("Expected Compact to keep timestamps, got age %v instead of %v", got, age)
+	}
+	if u.peak != 3 {
+		t.Errorf("Expected peak to be reset to 3, got %d", u.peak)
+	}
+
+	// The queue stays fully usable.
+	u.PushBack(97)
+	u.PushBack(1)
+	u.MoveToFront(1)
+	if !u.Remove(98) {
+		t.Error("Expected Remove to find an item after Compact")
+	}
+	assertOrder(t, u, []int{1, 97, 99})
+}
+
+func TestUniqueueUnsafe_AutoCompact(t *testing.T) {
+	u := NewUniqueueUnsafe[int](WithAutoCompact(16))
+	for i := range 100 {
+		u.PushBack(i)
+	}
+	if u.peak != 100 {
+		t.Fatalf("Expected peak 100, got %d", u.peak)
+	}
+
+	for range 74 {
+		u.PopHead()
+	}
+	if u.peak != 100 {
+		t.Errorf("Expected no compaction above a quarter of the peak, got peak %d", u.peak)
+	}
+	u.PopHead()
+	if u.peak != 25 {
+		t.Errorf("Expected compaction at a quarter of the peak, got peak %d", u.peak)
+	}
+
+	// Peaks below the threshold are left alone.
+	small := NewUniqueueUnsafe[int](WithAutoCompact(16))
+	for i := range 10 {
+		small.PushBack(i)
+	}
+	small.Retain(func(int) bool { return false })
+	if small.peak != 10 {
+		t.Errorf("Expected no compaction below the threshold, got peak %d", small.peak)
+	}
+}
+
+func TestUniqueue_Compact(t *testing.T) {
+	u := NewUniqueue[string](WithSizeHint(8))
+	u.PushBack("a")
+	u.PushBack("b")
+	u.PushBack("c")
+	item, id, err := u.Lease(t.Context(), time.Minute)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	u.Compact()
+	if !u.IsLeased(item) {
+		t.Errorf("Expected %s to stay leased after Compact", item)
+	}
+	if err := u.Ack(id); err != nil {
+		t.Errorf("Expected Ack to find the lease after Compact, got %v", err)
+	}
+	if got := u.PeekN(10); len(got) != 2 || got[0] != "b" || got[1] != "c" {
+		t.Errorf("Expected [b c], got %v", got)
+	}
+}
+
+func TestSizeOptions_Invalid(t *testing.T) {
+	for _, opt := range []Option{WithSizeHint(-1), WithAutoCompact(0)} {
+		if _, err := NewUniqueueWithOptions[int](opt); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("Expected ErrInvalidOption, got %v", err)
+		}
+	}
+	u := NewUniqueueUnsafe[int](WithSizeHint(1000), WithAutoCompact(10))
+	if c := u.Options(); c.SizeHint != 1000 || c.AutoCompact != 10 {
+		t.Errorf("Expected SizeHint 1000 and AutoCompact 10, got %+v", c)
+	}
+}
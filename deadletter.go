@@ -0,0 +1,113 @@
+package uniqueue
+
+// DeadLetter describes an item that exhausted its delivery attempts.
+type DeadLetter[T comparable] struct {
+	Item T
+	// Err is the error of the last failed delivery: the error passed to
+	// NackWithError, or ErrLeaseExpired if the last lease timed out.
+	Err      error
+	Attempts int
+}
+
+// deadLetters holds items moved out of a Uniqueue after too many failed
+// deliveries. All access must hold the Uniqueue lock.
+type deadLetters[T comparable] struct {
+	queue *UniqueueUnsafe[T]
+	info  map[T]DeadLetter[T]
+}
+
+func newDeadLetters[T comparable]() deadLetters[T] {
+	return deadLetters[T]{
+		queue: NewUniqueueUnsafe[T](),
+		info:  make(map[T]DeadLetter[T]),
+	}
+}
+
+func (d *deadLetters[T]) add(item T, err error, attempts int) {
+	d.queue.PushBack(item)
+	d.info[item] = DeadLetter[T]{Item: item, Err: err, Attempts: attempts}
+}
+
+func (d *deadLetters[T]) remove(item T) bool {
+	if !d.queue.Remove(item) {
+		return false
+	}
+	delete(d.info, item)
+	return true
+}
+
+// DeadLetters returns the dead-lettered items in the order they failed.
+func (u *Uniqueue[T]) DeadLetters() []DeadLetter[T] {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	items := u.dead.queue.PeekN(u.dead.queue.Size())
+	result := make([]DeadLetter[T], len(items))
+	for i, item := range items {
+		result[i] = u.dead.info[item]
+	}
+	return result
+}
+
+// DeadLetterCount returns the number of dead-lettered items.
+func (u *Uniqueue[T]) DeadLetterCount() int {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.dead.queue.Size()
+}
+
+// Redrive moves a dead-lettered item back to the tail of the queue with a
+// fresh delivery count. Returns false if the item is not dead-lettered.
+// If the item has been pushed again in the meantime it is only removed
+// from the dead-letter queue.
+func (u *Uniqueue[T]) Redrive(item T) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if !u.dead.remove(item) {
+		return false
+	}
+	u.redrive(item)
+	return true
+}
+
+// RedriveAll moves every dead-lettered item back to the tail of the queue
+// in the order they failed and returns how many were moved.
+func (u *Uniqueue[T]) RedriveAll() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	n := 0
+	for {
+		item, ok := u.dead.queue.PopHead()
+		if !ok {
+			break
+		}
+		delete(u.dead.info, item)
+		u.redrive(item)
+		n++
+	}
+	return n
+}
+
+// PurgeDeadLetters discards all dead-lettered items and returns how many
+// were discarded.
+func (u *Uniqueue[T]) PurgeDeadLetters() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	n := u.dead.queue.Size()
+	u.dead = newDeadLetters[T]()
+	return n
+}
+
+// redrive pushes a former dead letter back into the queue.
+// Must be called with the lock held.
+func (u *Uniqueue[T]) redrive(item T) {
+	if u.leases.holds(item) {
+		return
+	}
+	u.uniqueue.PushBack(item)
+	u.broadcast()
+}
@@ -0,0 +1,134 @@
+package uniqueue
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func leaseAndFail(t *testing.T, u *Uniqueue[string], err error) string {
+	t.Helper()
+	item, id, lerr := u.Lease(context.Background(), time.Minute)
+	if lerr != nil {
+		t.Fatalf("Lease failed: %v", lerr)
+	}
+	if nerr := u.NackWithError(id, err); nerr != nil {
+		t.Fatalf("NackWithError failed: %v", nerr)
+	}
+	return item
+}
+
+func TestUniqueue_DeadLetter_MaxDeliveries(t *testing.T) {
+	errBoom := errors.New("boom")
+	u := NewUniqueue[string](WithMaxDeliveries(2))
+	u.PushBack("poison")
+	u.PushBack("ok")
+
+	leaseAndFail(t, u, errors.New("first"))
+	if !u.Contains("poison") {
+		t.Fatal("Expected item to be retried after the first failure")
+	}
+
+	leaseAndFail(t, u, errBoom)
+	if u.Contains("poison") {
+		t.Error("Expected item to leave the queue after max deliveries")
+	}
+	if u.DeadLetterCount() != 1 {
+		t.Errorf("Expected 1 dead letter, got %d", u.DeadLetterCount())
+	}
+
+	expected := []DeadLetter[string]{{Item: "poison", Err: errBoom, Attempts: 2}}
+	if got := u.DeadLetters(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+
+	// The rest of the queue is unaffected.
+	if item, ok := u.Peek(); !ok || item != "ok" {
+		t.Errorf("Expected ('ok', true), got (%s, %v)", item, ok)
+	}
+}
+
+func TestUniqueue_DeadLetter_AckResetsAttempts(t *testing.T) {
+	u := NewUniqueue[string](WithMaxDeliveries(2))
+	u.PushBack("a")
+
+	leaseAndFail(t, u, nil)
+	_, id, _ := u.Lease(context.Background(), time.Minute)
+	u.Ack(id)
+
+	u.PushBack("a")
+	leaseAndFail(t, u, nil)
+	if u.DeadLetterCount() != 0 || !u.Contains("a") {
+		t.Error("Expected Ack to reset the delivery count")
+	}
+}
+
+func TestUniqueue_DeadLetter_Expiry(t *testing.T) {
+	u := NewUniqueue[string](WithMaxDeliveries(1))
+	u.PushBack("a")
+
+	u.Lease(context.Background(), 10*time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for u.DeadLetterCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	letters := u.DeadLetters()
+	if len(letters) != 1 || !errors.Is(letters[0].Err, ErrLeaseExpired) {
+		t.Errorf("Expected one dead letter with ErrLeaseExpired, got %+v", letters)
+	}
+}
+
+func TestUniqueue_DeadLetter_Redrive(t *testing.T) {
+	u := NewUniqueue[string](WithMaxDeliveries(1))
+	for _, item := range []string{"a", "b", "c"} {
+		u.PushBack(item)
+		leaseAndFail(t, u, nil)
+	}
+	if u.DeadLetterCount() != 3 {
+		t.Fatalf("Expected 3 dead letters, got %d", u.DeadLetterCount())
+	}
+
+	if !u.Redrive("b") {
+		t.Error("Expected Redrive to return true")
+	}
+	if u.Redrive("b") {
+		t.Error("Expected second Redrive to return false")
+	}
+	if got := u.PeekN(10); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("Expected [b], got %v", got)
+	}
+
+	// A redriven item starts with a fresh delivery count.
+	leaseAndFail(t, u, nil)
+	if u.DeadLetterCount() != 3 {
+		t.Errorf("Expected 3 dead letters, got %d", u.DeadLetterCount())
+	}
+
+	if n := u.RedriveAll(); n != 3 {
+		t.Errorf("Expected 3 redriven, got %d", n)
+	}
+	if got := u.PeekN(10); !reflect.DeepEqual(got, []string{"a", "c", "b"}) {
+		t.Errorf("Expected [a c b], got %v", got)
+	}
+	if u.DeadLetterCount() != 0 {
+		t.Errorf("Expected no dead letters, got %d", u.DeadLetterCount())
+	}
+}
+
+func TestUniqueue_DeadLetter_Purge(t *testing.T) {
+	u := NewUniqueue[string](WithMaxDeliveries(1))
+	u.PushBack("a")
+	u.PushBack("b")
+	leaseAndFail(t, u, nil)
+	leaseAndFail(t, u, nil)
+
+	if n := u.PurgeDeadLetters(); n != 2 {
+		t.Errorf("Expected 2 purged, got %d", n)
+	}
+	if u.DeadLetterCount() != 0 || len(u.DeadLetters()) != 0 {
+		t.Error("Expected dead-letter queue to be empty after purge")
+	}
+}
@@ -0,0 +1,143 @@
+package uniqueue
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultDebugItems is the number of head items reported by DebugHandler
+// when the request does not specify one.
+const DefaultDebugItems = 10
+
+// DebugInfo is a snapshot of a queue served by DebugHandler.
+type DebugInfo struct {
+	Size int `json:"size"`
+	// OldestAge is the age of the oldest item in seconds.
+	OldestAge float64 `json:"oldest_age_seconds"`
+	Items     []any   `json:"items"`
+	Stats     Stats   `json:"stats"`
+}
+
+// DebugSource is implemented by queues that can be inspected through
+// DebugHandler. *Uniqueue[T] implements it for every T.
+type DebugSource interface {
+	DebugInfo(n int) DebugInfo
+}
+
+// DebugHandler is an http.Handler that serves JSON snapshots of a set of
+// named queues. It is safe for concurrent use.
+//
+// GET requests return an object keyed by queue name. The "name" query
+// parameter restricts the response to a single queue, and "n" sets how many
+// head items are included (DefaultDebugItems by default).
+type DebugHandler struct {
+	mu     sync.RWMutex
+	queues map[string]DebugSource
+}
+
+// NewDebugHandler creates a handler with no registered queues.
+func NewDebugHandler() *DebugHandler {
+	return &DebugHandler{
+		queues: make(map[string]DebugSource),
+	}
+}
+
+// Register adds q under name, replacing any queue registered with the
+// same name.
+func (h *DebugHandler) Register(name string, q DebugSource) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.queues[name] = q
+}
+
+// Unregister removes the queue registered under name.
+func (h *DebugHandler) Unregister(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.queues, name)
+}
+
+// Snapshot returns DebugInfo for every registered queue, including up to n
+// head items of each.
+func (h *DebugHandler) Snapshot(n int) map[string]DebugInfo {
+	h.mu.RLock()
+	names := make([]string, 0, len(h.queues))
+	sources := make([]DebugSource, 0, len(h.queues))
+	for name, q := range h.queues {
+		names = append(names, name)
+		sources = append(sources, q)
+	}
+	h.mu.RUnlock()
+
+	result := make(map[string]DebugInfo, len(names))
+	for i, name := range names {
+		result[name] = sources[i].DebugInfo(n)
+	}
+	return result
+}
+
+// Names returns the registered queue names in sorted order.
+func (h *DebugHandler) Names() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	names := make([]string, 0, len(h.queues))
+	for name := range h.queues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Publish exposes the snapshot of all registered queues as the expvar
+// variable name, with DefaultDebugItems head items per queue.
+// Like expvar.Publish, it panics if name is already in use.
+func (h *DebugHandler) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return h.Snapshot(DefaultDebugItems)
+	}))
+}
+
+// ServeHTTP implements http.Handler.
+func (h *DebugHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	n := DefaultDebugItems
+	if s := r.URL.Query().Get("n"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 {
+			http.Error(w, "invalid n", http.StatusBadRequest)
+			return
+		}
+		n = v
+	}
+
+	var body any
+	if name := r.URL.Query().Get("name"); name != "" {
+		h.mu.RLock()
+		q, ok := h.queues[name]
+		h.mu.RUnlock()
+		if !ok {
+			http.Error(w, "unknown queue", http.StatusNotFound)
+			return
+		}
+		body = q.DebugInfo(n)
+	} else {
+		body = h.Snapshot(n)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(body)
+}
@@ -0,0 +1,135 @@
+package uniqueue
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDebugHandler(t *testing.T) {
+	clock := newFakeClock()
+	jobs := NewUniqueue[string](withClock(clock.Now))
+	jobs.PushBack("a")
+	clock.Advance(3 * time.Second)
+	jobs.PushBack("b")
+	jobs.PushBack("c")
+	jobs.PushBack("a") // duplicate
+
+	ids := NewUniqueue[int]()
+	ids.PushBack(7)
+
+	h := NewDebugHandler()
+	h.Register("jobs", jobs)
+	h.Register("ids", ids)
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	t.Run("all queues", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "?n=2")
+		if err != nil {
+			t.Fatalf("GET failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Expected JSON content type, got %q", ct)
+		}
+		var got map[string]DebugInfo
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+
+		info, ok := got["jobs"]
+		if !ok {
+			t.Fatalf("Expected jobs queue in response, got %v", got)
+		}
+		if info.Size != 3 {
+			t.Errorf("Expected size 3, got %d", info.Size)
+		}
+		if info.OldestAge != 3 {
+			t.Errorf("Expected oldest age 3s, got %v", info.OldestAge)
+		}
+		if !reflect.DeepEqual(info.Items, []any{"a", "b"}) {
+			t.Errorf("Expected items [a b], got %v", info.Items)
+		}
+		if info.Stats.Duplicates != 1 {
+			t.Errorf("Expected 1 duplicate hit, got %d", info.Stats.Duplicates)
+		}
+		if got["ids"].Size != 1 {
+			t.Errorf("Expected ids size 1, got %d", got["ids"].Size)
+		}
+	})
+
+	t.Run("single queue", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "?name=ids")
+		if err != nil {
+			t.Fatalf("GET failed: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var info DebugInfo
+		if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		// JSON numbers decode as float64.
+		if !reflect.DeepEqual(info.Items, []any{float64(7)}) {
+			t.Errorf("Expected items [7], got %v", info.Items)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			method string
+			query  string
+			status int
+		}{
+			{http.MethodGet, "?name=missing", http.StatusNotFound},
+			{http.MethodGet, "?n=-1", http.StatusBadRequest},
+			{http.MethodPost, "", http.StatusMethodNotAllowed},
+		} {
+			req, _ := http.NewRequest(tc.method, srv.URL+tc.query, nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("%s %s failed: %v", tc.method, tc.query, err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tc.status {
+				t.Errorf("%s %s: expected status %d, got %d", tc.method, tc.query, tc.status, resp.StatusCode)
+			}
+		}
+	})
+
+	h.Unregister("ids")
+	if names := h.Names(); !reflect.DeepEqual(names, []string{"jobs"}) {
+		t.Errorf("Expected [jobs] after Unregister, got %v", names)
+	}
+}
+
+func TestDebugHandler_Publish(t *testing.T) {
+	u := NewUniqueue[string]()
+	u.PushBack("x")
+
+	h := NewDebugHandler()
+	h.Register("q", u)
+	// expvar names are process-global, so use a fresh one on every run.
+	name := fmt.Sprintf("uniqueue_debug_test_%d", time.Now().UnixNano())
+	h.Publish(name)
+
+	v := expvar.Get(name)
+	if v == nil {
+		t.Fatal("Expected expvar to be published")
+	}
+	var got map[string]DebugInfo
+	if err := json.Unmarshal([]byte(v.String()), &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if got["q"].Size != 1 {
+		t.Errorf("Expected size 1, got %d", got["q"].Size)
+	}
+}
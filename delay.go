@@ -0,0 +1,178 @@
+package uniqueue
+
+import "time"
+
+// delays holds back pushes of a Uniqueue configured with WithDebounce or
+// WithThrottle. All access must hold the Uniqueue lock.
+type delays[T comparable] struct {
+	debounce time.Duration
+	throttle time.Duration
+	// held has an entry for every item that is pending and, when
+	// throttling, for every item released less than throttle ago.
+	held map[T]*hold
+}
+
+type hold struct {
+	// pending reports whether a push waits to become visible, and front
+	// whether it goes to the head of the queue.
+	pending bool
+	front   bool
+	// deadline is when the timer should act; it may be moved forward
+	// without resetting the timer.
+	deadline time.Time
+	timer    *time.Timer
+}
+
+func newDelays[T comparable](o *options) delays[T] {
+	d := delays[T]{debounce: o.debounce, throttle: o.throttle}
+	if d.enabled() {
+		d.held = make(map[T]*hold)
+	}
+	return d
+}
+
+func (d *delays[T]) enabled() bool {
+	return d.debounce > 0 || d.throttle > 0
+}
+
+// isPending reports whether a push of item is waiting to become visible.
+func (d *delays[T]) isPending(item T) bool {
+	h, ok := d.held[item]
+	return ok && h.pending
+}
+
+// holdPush decides what to do with a push of item that is neither queued
+// nor leased. It returns true if the item should be inserted right away;
+// otherwise the push is held back and released later by the timer.
+// Must be called with the lock held.
+func (u *Uniqueue[T]) holdPush(item T, front bool) bool {
+	d := &u.delays
+	h, ok := d.held[item]
+	now := time.Now()
+
+	if d.debounce > 0 {
+		if ok {
+			h.deadline = now.Add(d.debounce)
+			h.front = front
+			u.uniqueue.duplicate(item)
+			return false
+		}
+		u.holdBack(item, &hold{pending: true, front: front}, d.debounce)
+		return false
+	}
+
+	if !ok {
+		// Released now; remember it so that pushes in the next interval
+		// are held back.
+		u.holdBack(item, &hold{}, d.throttle)
+		return true
+	}
+	if h.pending {
+		u.uniqueue.duplicate(item)
+		return false
+	}
+	h.pending = true
+	h.front = front
+	return false
+}
+
+// holdBack registers h for item and arms its timer for interval.
+func (u *Uniqueue[T]) holdBack(item T, h *hold, interval time.Duration) {
+	h.deadline = time.Now().Add(interval)
+	h.timer = time.AfterFunc(interval, func() { u.release(item, h) })
+	u.delays.held[item] = h
+}
+
+// release is run by the timer of h.
+func (u *Uniqueue[T]) release(item T, h *hold) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	// h may have been dropped, or its deadline moved, after the timer
+	// fired but before the lock was acquired.
+	if u.delays.held[item] != h {
+		return
+	}
+	if remaining := time.Until(h.deadline); remaining > 0 {
+		h.timer.Reset(remaining)
+		return
+	}
+
+	if !h.pending {
+		delete(u.delays.held, item)
+		return
+	}
+	if u.delays.throttle > 0 {
+		// Start the next interval from this release.
+		h.pending = false
+		h.deadline = time.Now().Add(u.delays.throttle)
+		h.timer.Reset(u.delays.throttle)
+	} else {
+		delete(u.delays.held, item)
+	}
+
+	if u.leases.holds(item) {
+		u.uniqueue.duplicate(item)
+		return
+	}
+	if h.front {
+		u.uniqueue.PushFront(item)
+	} else {
+		u.uniqueue.PushBack(item)
+	}
+	u.broadcast()
+}
+
+// cancel drops a pending push of item, keeping the throttle interval.
+// Returns false if none was pending. Must be called with the lock held.
+func (d *delays[T]) cancel(item T) bool {
+	h, ok := d.held[item]
+	if !ok || !h.pending {
+		return false
+	}
+	if d.throttle > 0 {
+		h.pending = false
+	} else {
+		h.timer.Stop()
+		delete(d.held, item)
+	}
+	return true
+}
+
+// cancelAll drops every pending push. Must be called with the lock held.
+func (d *delays[T]) cancelAll() {
+	for item := range d.held {
+		d.cancel(item)
+	}
+}
+
+// pending returns the number of pending pushes.
+func (d *delays[T]) pending() int {
+	n := 0
+	for _, h := range d.held {
+		if h.pending {
+			n++
+		}
+	}
+	return n
+}
+
+// Pending returns the number of items held back by WithDebounce or
+// WithThrottle that are not yet visible.
+// Time complexity: O(n) where n is the number of held items
+func (u *Uniqueue[T]) Pending() int {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.delays.pending()
+}
+
+// IsPending reports whether a push of item is held back by WithDebounce
+// or WithThrottle.
+// Time complexity: O(1)
+func (u *Uniqueue[T]) IsPending(item T) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.delays.isPending(item)
+}
@@ -0,0 +1,161 @@
+package uniqueue
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// eventually polls cond until it holds or a second has passed.
+func eventually(t *testing.T, cond func() bool, msg string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal(msg)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestUniqueue_Debounce(t *testing.T) {
+	const interval = 30 * time.Millisecond
+	u := NewUniqueue[string](WithDebounce(interval))
+
+	start := time.Now()
+	u.PushBack("a")
+	if u.Contains("a") || u.Size() != 0 {
+		t.Error("Expected a debounced item to be invisible while pending")
+	}
+	if !u.IsPending("a") || u.Pending() != 1 {
+		t.Errorf("Expected a to be pending, got %d pending", u.Pending())
+	}
+
+	// Further pushes during the burst restart the wait.
+	for range 3 {
+		time.Sleep(interval / 2)
+		u.PushBack("a")
+	}
+	if u.Contains("a") {
+		t.Error("Expected pushes during the burst to keep a pending")
+	}
+
+	item, err := u.PopHeadWait(t.Context())
+	if err != nil || item != "a" {
+		t.Fatalf("Expected (a, nil), got (%s, %v)", item, err)
+	}
+	if elapsed := time.Since(start); elapsed < interval*5/2 {
+		t.Errorf("Expected a to be released after the burst, got it after %v", elapsed)
+	}
+	if u.IsPending("a") {
+		t.Error("Expected a not to be pending after release")
+	}
+
+	expected := Stats{Adds: 1, Duplicates: 3, Pops: 1}
+	if got := u.Stats(); got != expected {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestUniqueue_Debounce_Cancel(t *testing.T) {
+	u := NewUniqueue[string](WithDebounce(time.Hour))
+	u.PushBack("a")
+	u.Remove("a")
+	if u.IsPending("a") {
+		t.Error("Expected Remove to cancel the pending push")
+	}
+
+	u.PushBack("b")
+	u.PushFront("c")
+	u.Clear()
+	if u.Pending() != 0 {
+		t.Errorf("Expected Clear to cancel pending pushes, got %d", u.Pending())
+	}
+}
+
+func TestUniqueue_Throttle(t *testing.T) {
+	const interval = 40 * time.Millisecond
+	u := NewUniqueue[string](WithThrottle(interval))
+
+	u.PushBack("a")
+	if !u.Contains("a") {
+		t.Fatal("Expected the first push to be visible immediately")
+	}
+	start := time.Now()
+	u.PopHead()
+
+	// Within the interval the next push is held back and repeats are
+	// ignored.
+	u.PushBack("a")
+	u.PushBack("a")
+	if u.Contains("a") || !u.IsPending("a") {
+		t.Error("Expected a push within the interval to be held back")
+	}
+	u.PushBack("b")
+	if !u.Contains("b") {
+		t.Error("Expected other items not to be throttled")
+	}
+
+	eventually(t, func() bool { return u.Contains("a") }, "a was not released")
+	if elapsed := time.Since(start); elapsed < interval*3/4 {
+		t.Errorf("Expected a to be released after the interval, got it after %v", elapsed)
+	}
+
+	expected := Stats{Adds: 3, Duplicates: 1, Pops: 1}
+	if got := u.Stats(); got != expected {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+
+	// Once an interval passes without pushes the item is forgotten.
+	u.Clear()
+	eventually(t, func() bool {
+		u.mu.RLock()
+		defer u.mu.RUnlock()
+		return len(u.delays.held) == 0
+	}, "throttle records were not dropped")
+	u.PushBack("a")
+	if !u.Contains("a") {
+		t.Error("Expected a push after a quiet interval to be visible immediately")
+	}
+}
+
+func TestUniqueue_Throttle_Leased(t *testing.T) {
+	u := NewUniqueue[string](WithThrottle(20 * time.Millisecond))
+	u.PushBack("a")
+	_, id, err := u.Lease(t.Context(), time.Minute)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	u.PushBack("a")
+	if u.IsPending("a") {
+		t.Error("Expected a push of a leased item to be ignored, not held back")
+	}
+	u.Ack(id)
+}
+
+func TestDelayOptions_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"zero debounce", []Option{WithDebounce(0)}},
+		{"negative throttle", []Option{WithThrottle(-time.Second)}},
+		{"both", []Option{WithDebounce(time.Second), WithThrottle(time.Second)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewUniqueueWithOptions[int](tt.opts...); !errors.Is(err, ErrInvalidOption) {
+				t.Errorf("Expected ErrInvalidOption, got %v", err)
+			}
+		})
+	}
+	if _, err := NewUniqueueUnsafeWithOptions[int](WithDebounce(time.Second)); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Expected UniqueueUnsafe to reject WithDebounce, got %v", err)
+	}
+
+	u := NewUniqueue[int](WithThrottle(time.Second))
+	if c := u.Options(); c.Throttle != time.Second || c.Debounce != 0 {
+		t.Errorf("Expected Throttle 1s, got %+v", c)
+	}
+}
@@ -0,0 +1,326 @@
+package uniqueue
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// listInvariants checks the links of l: head and tail terminate the list,
+// prev mirrors next, length matches the number of nodes, and the node
+// pool stays within its limit.
+func listInvariants[T any](l *List[T]) error {
+	if (l.head == nil) != (l.tail == nil) {
+		return fmt.Errorf("head %p and tail %p disagree on emptiness", l.head, l.tail)
+	}
+	if l.head != nil && l.head.prev != nil {
+		return fmt.Errorf("head has a prev node")
+	}
+	if l.tail != nil && l.tail.next != nil {
+		return fmt.Errorf("tail has a next node")
+	}
+
+	n := 0
+	var prev *node[T]
+	for cur := l.head; cur != nil; cur = cur.next {
+		if cur.prev != prev {
+			return fmt.Errorf("node %d: prev does not point to the previous node", n)
+		}
+		prev = cur
+		n++
+		if n > l.length {
+			return fmt.Errorf("more nodes than length %d, or a cycle", l.length)
+		}
+	}
+	if prev != l.tail {
+		return fmt.Errorf("walking next from head does not end at tail")
+	}
+	if n != l.length {
+		return fmt.Errorf("length is %d, but the list has %d nodes", l.length, n)
+	}
+
+	free := 0
+	for cur := l.free; cur != nil; cur = cur.next {
+		free++
+		if free > l.freeLen {
+			break
+		}
+	}
+	if free != l.freeLen || l.freeLen > l.freeMax {
+		return fmt.Errorf("node pool holds %d nodes, freeLen %d, freeMax %d", free, l.freeLen, l.freeMax)
+	}
+	return nil
+}
+
+// uniqueueInvariants checks the lists of u, that seen indexes exactly
+// their nodes, and that byAge is ordered by enqueue time.
+func uniqueueInvariants[T comparable](u *UniqueueUnsafe[T]) error {
+	if err := listInvariants(u.queue); err != nil {
+		return err
+	}
+	if err := listInvariants(u.byAge); err != nil {
+		return fmt.Errorf("byAge: %w", err)
+	}
+	if u.byAge.length != u.queue.length {
+		return fmt.Errorf("byAge has %d nodes, but the list has %d", u.byAge.length, u.queue.length)
+	}
+	for cur := u.byAge.head; cur != nil; cur = cur.next {
+		e, ok := u.seen[cur.value]
+		if !ok || e.age != cur {
+			return fmt.Errorf("seen does not map item %v to its byAge node", cur.value)
+		}
+		if cur.next != nil && u.seen[cur.next.value].enqueued.Before(e.enqueued) {
+			return fmt.Errorf("byAge puts %v before the older %v", cur.value, cur.next.value)
+		}
+	}
+	if len(u.seen) != u.queue.length {
+		return fmt.Errorf("seen has %d entries, but the list has %d nodes", len(u.seen), u.queue.length)
+	}
+	for cur := u.queue.head; cur != nil; cur = cur.next {
+		e, ok := u.seen[cur.value]
+		if !ok {
+			return fmt.Errorf("item %v is not in seen", cur.value)
+		}
+		if e.node != cur {
+			return fmt.Errorf("seen maps item %v to another node", cur.value)
+		}
+	}
+	return nil
+}
+
+// sliceModel is the reference the fuzz targets are checked against.
+type sliceModel struct {
+	items  []int
+	unique bool
+}
+
+func (m *sliceModel) pushBack(x int) {
+	if !m.unique || !slices.Contains(m.items, x) {
+		m.items = append(m.items, x)
+	}
+}
+
+func (m *sliceModel) pushFront(x int) {
+	if !m.unique || !slices.Contains(m.items, x) {
+		m.items = slices.Insert(m.items, 0, x)
+	}
+}
+
+func (m *sliceModel) popHead() (int, bool) {
+	if len(m.items) == 0 {
+		return 0, false
+	}
+	x := m.items[0]
+	m.items = m.items[1:]
+	return x, true
+}
+
+func (m *sliceModel) popTail() (int, bool) {
+	if len(m.items) == 0 {
+		return 0, false
+	}
+	x := m.items[len(m.items)-1]
+	m.items = m.items[:len(m.items)-1]
+	return x, true
+}
+
+// remove drops every occurrence of x.
+func (m *sliceModel) remove(x int) bool {
+	n := len(m.items)
+	m.items = slices.DeleteFunc(m.items, func(v int) bool { return v == x })
+	return len(m.items) < n
+}
+
+// fuzzTarget adapts a queue to the operations of sliceModel.
+type fuzzTarget interface {
+	pushBack(x int)
+	// pushFront returns false if the queue cannot push to the front.
+	pushFront(x int) bool
+	popHead() (int, bool)
+	popTail() (int, bool)
+	contains(x int) bool
+	remove(x int) bool
+	items() []int
+	invariants() error
+}
+
+type queueTarget struct{ q *Queue[int] }
+
+func (q queueTarget) pushBack(x int)       { q.q.PushBack(x) }
+func (q queueTarget) pushFront(int) bool   { return false }
+func (q queueTarget) popHead() (int, bool) { return q.q.PopHead() }
+func (q queueTarget) popTail() (int, bool) { return q.q.PopTail() }
+func (q queueTarget) contains(x int) bool  { return q.q.Contains(x) }
+func (q queueTarget) remove(x int) bool    { return q.q.Retain(func(v int) bool { return v != x }) > 0 }
+func (q queueTarget) items() []int         { return q.q.PeekN(q.q.Size()) }
+func (q queueTarget) invariants() error    { return listInvariants(&q.q.List) }
+
+type unsafeTarget struct{ u *UniqueueUnsafe[int] }
+
+func (u unsafeTarget) pushBack(x int)       { u.u.PushBack(x) }
+func (u unsafeTarget) pushFront(x int) bool { u.u.PushFront(x); return true }
+func (u unsafeTarget) popHead() (int, bool) { return u.u.PopHead() }
+func (u unsafeTarget) popTail() (int, bool) { return u.u.PopTail() }
+func (u unsafeTarget) contains(x int) bool  { return u.u.Contains(x) }
+func (u unsafeTarget) remove(x int) bool    { return u.u.Remove(x) }
+func (u unsafeTarget) items() []int         { return u.u.PeekN(u.u.Size()) }
+func (u unsafeTarget) invariants() error    { return uniqueueInvariants(u.u) }
+
+type uniqueueTarget struct{ u *Uniqueue[int] }
+
+func (u uniqueueTarget) pushBack(x int)       { u.u.PushBack(x) }
+func (u uniqueueTarget) pushFront(x int) bool { u.u.PushFront(x); return true }
+func (u uniqueueTarget) popHead() (int, bool) { return u.u.PopHead() }
+func (u uniqueueTarget) popTail() (int, bool) { return u.u.PopTail() }
+func (u uniqueueTarget) contains(x int) bool  { return u.u.Contains(x) }
+func (u uniqueueTarget) remove(x int) bool    { return u.u.Remove(x) }
+func (u uniqueueTarget) items() []int         { return u.u.PeekN(u.u.Size()) }
+
+func (u uniqueueTarget) invariants() error {
+	u.u.mu.RLock()
+	defer u.u.mu.RUnlock()
+
+	if len(u.u.leases.byItem) > 0 {
+		return fmt.Errorf("unexpected leases")
+	}
+	return uniqueueInvariants(u.u.uniqueue)
+}
+
+// runOps interprets data as pairs of an operation and an item, applies
+// each pair to target and model, and fails on the first difference.
+// Items are taken from a small range so that duplicates are common.
+func runOps(t *testing.T, target fuzzTarget, model *sliceModel, data []byte) {
+	t.Helper()
+	for i := 0; i+1 < len(data); i += 2 {
+		op, x := data[i]%6, int(data[i+1]%16)
+		var name string
+		switch op {
+		case 0:
+			name = fmt.Sprintf("pushBack(%d)", x)
+			target.pushBack(x)
+			model.pushBack(x)
+		case 1:
+			name = fmt.Sprintf("pushFront(%d)", x)
+			if target.pushFront(x) {
+				model.pushFront(x)
+			}
+		case 2:
+			name = "popHead()"
+			got, gotOK := target.popHead()
+			expected, expectedOK := model.popHead()
+			if got != expected || gotOK != expectedOK {
+				t.Fatalf("op %d %s: expected (%d, %v), got (%d, %v)", i/2, name, expected, expectedOK, got, gotOK)
+			}
+		case 3:
+			name = "popTail()"
+			got, gotOK := target.popTail()
+			expected, expectedOK := model.popTail()
+			if got != expected || gotOK != expectedOK {
+				t.Fatalf("op %d %s: expected (%d, %v), got (%d, %v)", i/2, name, expected, expectedOK, got, gotOK)
+			}
+		case 4:
+			name = fmt.Sprintf("contains(%d)", x)
+			if got, expected := target.contains(x), slices.Contains(model.items, x); got != expected {
+				t.Fatalf("op %d %s: expected %v, got %v", i/2, name, expected, got)
+			}
+		case 5:
+			name = fmt.Sprintf("remove(%d)", x)
+			if got, expected := target.remove(x), model.remove(x); got != expected {
+				t.Fatalf("op %d %s: expected %v, got %v", i/2, name, expected, got)
+			}
+		}
+
+		if err := target.invariants(); err != nil {
+			t.Fatalf("op %d %s: broken invariant: %v", i/2, name, err)
+		}
+		if got := target.items(); !slices.Equal(got, model.items) {
+			t.Fatalf("op %d %s: expected items %v, got %v", i/2, name, model.items, got)
+		}
+	}
+}
+
+func addFuzzSeeds(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0, 1, 0, 2, 0, 1, 2, 0, 2, 0})
+	f.Add([]byte{1, 3, 0, 4, 1, 4, 3, 0, 5, 3, 4, 3})
+	f.Add([]byte{0, 1, 0, 2, 0, 3, 5, 2, 2, 0, 3, 0, 3, 0})
+}
+
+func FuzzQueue(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		q := NewQueue[int]()
+		q.SetNodePool(4)
+		runOps(t, queueTarget{q}, &sliceModel{}, data)
+	})
+}
+
+func FuzzUniqueueUnsafe(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		u := NewUniqueueUnsafe[int](WithNodePool(4), WithAutoCompact(8))
+		runOps(t, unsafeTarget{u}, &sliceModel{unique: true}, data)
+	})
+}
+
+func FuzzUniqueue(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		u := NewUniqueue[int](WithNodePool(4))
+		runOps(t, uniqueueTarget{u}, &sliceModel{unique: true}, data)
+	})
+}
+
+func TestInvariants_StructuralOps(t *testing.T) {
+	check := func(step string, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: broken invariant: %v", step, err)
+		}
+	}
+
+	q := NewQueue[int]()
+	for i := range 10 {
+		q.PushBack(i)
+	}
+	rest := q.SplitAt(4)
+	check("SplitAt head", listInvariants(&q.List))
+	check("SplitAt rest", listInvariants(&rest.List))
+	q.Concat(rest)
+	check("Concat", listInvariants(&q.List))
+	check("Concat source", listInvariants(&rest.List))
+	q.Replace(3, 30)
+	q.Retain(func(v int) bool { return v%2 == 0 })
+	check("Retain", listInvariants(&q.List))
+
+	u := NewUniqueueUnsafe[int](WithNodePool(2))
+	for i := range 8 {
+		u.PushBack(i)
+	}
+	u.MoveToFront(5)
+	u.MoveToBack(0)
+	u.MoveBefore(7, 1)
+	u.MoveAfter(2, 6)
+	u.MoveBefore(4, 4)
+	check("Move", uniqueueInvariants(u))
+	u.Replace(3, 30)
+	u.Replace(30, 1)
+	check("Replace", uniqueueInvariants(u))
+
+	other := NewUniqueueUnsafe[int]()
+	for _, x := range []int{1, 40, 5, 50} {
+		other.PushBack(x)
+	}
+	u.Merge(other, MergeRelocateDuplicates)
+	check("Merge", uniqueueInvariants(u))
+	check("Merge source", uniqueueInvariants(other))
+
+	clone := u.Clone()
+	check("Clone", uniqueueInvariants(clone))
+	u.Retain(func(v int) bool { return v > 4 })
+	check("Retain", uniqueueInvariants(u))
+	u.Compact()
+	check("Compact", uniqueueInvariants(u))
+	u.Clear()
+	check("Clear", uniqueueInvariants(u))
+}
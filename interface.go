@@ -0,0 +1,31 @@
+package uniqueue
+
+import "context"
+
+// Interface is the method set shared by Queue, UniqueueUnsafe and
+// Uniqueue, so that code can be written against any of them or a mock.
+// Whether PushBack ignores items already in the queue depends on the
+// implementation.
+type Interface[T comparable] interface {
+	PushBack(item T)
+	PopHead() (T, bool)
+	Contains(item T) bool
+	Size() int
+	IsEmpty() bool
+}
+
+// BlockingInterface is an Interface that can wait for an item to arrive.
+// Implementations must be safe for concurrent use.
+type BlockingInterface[T comparable] interface {
+	Interface[T]
+	// PopHeadWait removes and returns the first item, blocking until one
+	// is available or ctx is done, in which case ctx.Err() is returned.
+	PopHeadWait(ctx context.Context) (T, error)
+}
+
+var (
+	_ Interface[int]         = (*Queue[int])(nil)
+	_ Interface[int]         = (*UniqueueUnsafe[int])(nil)
+	_ Interface[int]         = (*Uniqueue[int])(nil)
+	_ BlockingInterface[int] = (*Uniqueue[int])(nil)
+)
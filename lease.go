@@ -0,0 +1,251 @@
+package uniqueue
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// LeaseID identifies an item handed out by Uniqueue.Lease.
+type LeaseID uint64
+
+// RequeuePosition selects where a leased item goes back into the queue.
+type RequeuePosition int
+
+const (
+	// RequeueHead puts the item back at the head so it is retried first.
+	RequeueHead RequeuePosition = iota
+	// RequeueTail puts the item back at the tail behind all waiting items.
+	RequeueTail
+)
+
+var (
+	// ErrLeaseNotFound is returned for a lease that was already
+	// acknowledged, negatively acknowledged or has expired.
+	ErrLeaseNotFound = errors.New("uniqueue: lease not found")
+	// ErrInvalidTimeout is returned for a non-positive lease timeout.
+	ErrInvalidTimeout = errors.New("uniqueue: lease timeout must be positive")
+	// ErrLeaseExpired is recorded as the last error of an item whose
+	// lease expired without being acknowledged.
+	ErrLeaseExpired = errors.New("uniqueue: lease expired")
+)
+
+type lease[T comparable] struct {
+	item     T
+	deadline time.Time
+	timer    *time.Timer
+	// enqueued is when the item was first queued; it keeps that time if it
+	// is requeued so that OldestAge counts the whole wait.
+	enqueued time.Time
+}
+
+// leases tracks the items of a Uniqueue that are currently leased.
+// All access must hold the Uniqueue lock.
+type leases[T comparable] struct {
+	byID    map[LeaseID]*lease[T]
+	byItem  map[T]LeaseID
+	lastID  LeaseID
+	requeue RequeuePosition
+	// attempts counts deliveries of items that were leased but not yet
+	// acknowledged, including while they wait in the queue for a retry.
+	attempts      map[T]int
+	maxDeliveries int
+}
+
+func newLeases[T comparable](o *options) leases[T] {
+	return leases[T]{
+		byID:          make(map[LeaseID]*lease[T]),
+		byItem:        make(map[T]LeaseID),
+		requeue:       o.requeue,
+		attempts:      make(map[T]int),
+		maxDeliveries: o.maxDeliveries,
+	}
+}
+
+// compact rebuilds the lease indexes at their current size.
+func (l *leases[T]) compact() {
+	byID := make(map[LeaseID]*lease[T], len(l.byID))
+	for id, ls := range l.byID {
+		byID[id] = ls
+	}
+	byItem := make(map[T]LeaseID, len(l.byItem))
+	for item, id := range l.byItem {
+		byItem[item] = id
+	}
+	attempts := make(map[T]int, len(l.attempts))
+	for item, n := range l.attempts {
+		attempts[item] = n
+	}
+	l.byID, l.byItem, l.attempts = byID, byItem, attempts
+}
+
+// holds reports whether item is currently leased.
+func (l *leases[T]) holds(item T) bool {
+	_, ok := l.byItem[item]
+	return ok
+}
+
+func (l *leases[T]) remove(id LeaseID) (*lease[T], bool) {
+	ls, ok := l.byID[id]
+	if !ok {
+		return nil, false
+	}
+	ls.timer.Stop()
+	delete(l.byID, id)
+	delete(l.byItem, ls.item)
+	return ls, true
+}
+
+// Lease pops the first item and hides it for timeout instead of deleting
+// it. The item must be acknowledged with Ack before the lease expires,
+// otherwise it automatically returns to the queue (see WithLeaseRequeue).
+// While an item is leased, pushes of the same item are ignored as
+// duplicates.
+//
+// Lease blocks until an item is available or ctx is done, in which case
+// ctx.Err() is returned.
+func (u *Uniqueue[T]) Lease(ctx context.Context, timeout time.Duration) (T, LeaseID, error) {
+	var (
+		item T
+		id   LeaseID
+	)
+	if timeout <= 0 {
+		return item, 0, ErrInvalidTimeout
+	}
+	err := u.wait(ctx, func() bool {
+		enqueued := u.uniqueue.headEnqueued()
+		var ok bool
+		item, ok = u.uniqueue.PopHead()
+		if !ok {
+			return false
+		}
+		id = u.lease(item, timeout, enqueued)
+		u.broadcast()
+		return true
+	})
+	return item, id, err
+}
+
+// lease registers a lease on item. Must be called with the lock held.
+func (u *Uniqueue[T]) lease(item T, timeout time.Duration, enqueued time.Time) LeaseID {
+	u.leases.attempts[item]++
+	u.leases.lastID++
+	id := u.leases.lastID
+	u.leases.byID[id] = &lease[T]{
+		item:     item,
+		deadline: time.Now().Add(timeout),
+		timer:    time.AfterFunc(timeout, func() { u.expire(id) }),
+		enqueued: enqueued,
+	}
+	u.leases.byItem[item] = id
+	return id
+}
+
+// Ack acknowledges that the leased item was processed and releases it.
+func (u *Uniqueue[T]) Ack(id LeaseID) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	ls, ok := u.leases.remove(id)
+	if !ok {
+		return ErrLeaseNotFound
+	}
+	delete(u.leases.attempts, ls.item)
+	return nil
+}
+
+// Nack releases the lease and returns the item to the queue immediately.
+// It is equivalent to NackWithError(id, nil).
+func (u *Uniqueue[T]) Nack(id LeaseID) error {
+	return u.NackWithError(id, nil)
+}
+
+// NackWithError releases the lease and returns the item to the queue
+// immediately, recording err as the reason processing failed. If the item
+// has reached the limit set by WithMaxDeliveries it is moved to the
+// dead-letter queue instead, with err attached.
+func (u *Uniqueue[T]) NackWithError(id LeaseID, err error) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	ls, ok := u.leases.remove(id)
+	if !ok {
+		return ErrLeaseNotFound
+	}
+	u.retry(ls, err)
+	return nil
+}
+
+// Extend moves the lease deadline to d from now.
+func (u *Uniqueue[T]) Extend(id LeaseID, d time.Duration) error {
+	if d <= 0 {
+		return ErrInvalidTimeout
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	ls, ok := u.leases.byID[id]
+	if !ok {
+		return ErrLeaseNotFound
+	}
+	ls.deadline = time.Now().Add(d)
+	ls.timer.Reset(d)
+	return nil
+}
+
+// Leased returns the number of items currently leased.
+func (u *Uniqueue[T]) Leased() int {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return len(u.leases.byID)
+}
+
+// IsLeased reports whether item is currently leased.
+func (u *Uniqueue[T]) IsLeased(item T) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.leases.holds(item)
+}
+
+// expire returns the item of an expired lease to the queue.
+func (u *Uniqueue[T]) expire(id LeaseID) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	ls, ok := u.leases.byID[id]
+	if !ok {
+		return
+	}
+	// The lease may have been extended after the timer fired but before
+	// the lock was acquired.
+	if remaining := time.Until(ls.deadline); remaining > 0 {
+		ls.timer.Reset(remaining)
+		return
+	}
+	u.leases.remove(id)
+	u.retry(ls, ErrLeaseExpired)
+}
+
+// retry requeues a formerly leased item, or dead-letters it once it has
+// been delivered the maximum number of times.
+// Must be called with the lock held.
+func (u *Uniqueue[T]) retry(ls *lease[T], err error) {
+	attempts := u.leases.attempts[ls.item]
+	if u.leases.maxDeliveries > 0 && attempts >= u.leases.maxDeliveries {
+		delete(u.leases.attempts, ls.item)
+		u.dead.add(ls.item, err, attempts)
+		return
+	}
+	u.requeue(ls)
+}
+
+// requeue puts a formerly leased item back into the queue with its
+// original enqueue time.
+// Must be called with the lock held.
+func (u *Uniqueue[T]) requeue(ls *lease[T]) {
+	u.uniqueue.pushEnqueued(ls.item, u.leases.requeue != RequeueTail, ls.enqueued)
+	u.broadcast()
+}
@@ -0,0 +1,199 @@
+package uniqueue
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestUniqueue_Lease_Ack(t *testing.T) {
+	u := NewUniqueue[string]()
+	u.PushBack("a")
+	u.PushBack("b")
+
+	item, id, err := u.Lease(context.Background(), time.Minute)
+	if err != nil || item != "a" {
+		t.Fatalf("Expected ('a', nil), got (%s, %v)", item, err)
+	}
+	if u.Size() != 1 {
+		t.Errorf("Expected size 1 while leased, got %d", u.Size())
+	}
+	if !u.IsLeased("a") || u.Leased() != 1 {
+		t.Error("Expected 'a' to be leased")
+	}
+
+	// A re-push of a leased key is deduplicated against the lease.
+	u.PushBack("a")
+	if u.Contains("a") {
+		t.Error("Expected push of leased item to be ignored")
+	}
+
+	if err := u.Ack(id); err != nil {
+		t.Errorf("Expected Ack to succeed, got %v", err)
+	}
+	if err := u.Ack(id); !errors.Is(err, ErrLeaseNotFound) {
+		t.Errorf("Expected ErrLeaseNotFound for second Ack, got %v", err)
+	}
+	if u.IsLeased("a") {
+		t.Error("Expected 'a' to be released after Ack")
+	}
+
+	u.PushBack("a")
+	if !u.Contains("a") {
+		t.Error("Expected item to be pushable after Ack")
+	}
+}
+
+func TestUniqueue_Lease_Nack(t *testing.T) {
+	t.Run("requeue head", func(t *testing.T) {
+		u := NewUniqueue[int]()
+		u.PushBack(1)
+		u.PushBack(2)
+
+		_, id, _ := u.Lease(context.Background(), time.Minute)
+		if err := u.Nack(id); err != nil {
+			t.Fatalf("Expected Nack to succeed, got %v", err)
+		}
+		if got := u.PeekN(2); !reflect.DeepEqual(got, []int{1, 2}) {
+			t.Errorf("Expected [1 2], got %v", got)
+		}
+		if err := u.Nack(id); !errors.Is(err, ErrLeaseNotFound) {
+			t.Errorf("Expected ErrLeaseNotFound, got %v", err)
+		}
+	})
+
+	t.Run("requeue tail", func(t *testing.T) {
+		u := NewUniqueue[int](WithLeaseRequeue(RequeueTail))
+		u.PushBack(1)
+		u.PushBack(2)
+
+		_, id, _ := u.Lease(context.Background(), time.Minute)
+		u.Nack(id)
+		if got := u.PeekN(2); !reflect.DeepEqual(got, []int{2, 1}) {
+			t.Errorf("Expected [2 1], got %v", got)
+		}
+	})
+}
+
+func TestUniqueue_Lease_Expiry(t *testing.T) {
+	u := NewUniqueue[string]()
+	u.PushBack("a")
+
+	_, id, err := u.Lease(context.Background(), 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Lease failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := u.WaitContains(ctx, "a"); err != nil {
+		t.Fatalf("Expected expired item to return to the queue, got %v", err)
+	}
+	if u.IsLeased("a") {
+		t.Error("Expected lease to be released after expiry")
+	}
+	if err := u.Ack(id); !errors.Is(err, ErrLeaseNotFound) {
+		t.Errorf("Expected ErrLeaseNotFound after expiry, got %v", err)
+	}
+}
+
+func TestUniqueue_Lease_Extend(t *testing.T) {
+	u := NewUniqueue[string]()
+	u.PushBack("a")
+
+	_, id, _ := u.Lease(context.Background(), 20*time.Millisecond)
+	if err := u.Extend(id, time.Minute); err != nil {
+		t.Fatalf("Expected Extend to succeed, got %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if u.Contains("a") || !u.IsLeased("a") {
+		t.Error("Expected extended lease not to expire")
+	}
+
+	if err := u.Extend(id, 0); !errors.Is(err, ErrInvalidTimeout) {
+		t.Errorf("Expected ErrInvalidTimeout, got %v", err)
+	}
+	if err := u.Extend(LeaseID(999), time.Second); !errors.Is(err, ErrLeaseNotFound) {
+		t.Errorf("Expected ErrLeaseNotFound, got %v", err)
+	}
+	u.Ack(id)
+}
+
+func TestUniqueue_Lease_Blocks(t *testing.T) {
+	u := NewUniqueue[int]()
+
+	type result struct {
+		item int
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		item, _, err := u.Lease(context.Background(), time.Minute)
+		done <- result{item, err}
+	}()
+
+	select {
+	case r := <-done:
+		t.Fatalf("Expected Lease to block on empty queue, got %+v", r)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	u.PushBack(42)
+	select {
+	case r := <-done:
+		if r.err != nil || r.item != 42 {
+			t.Errorf("Expected (42, nil), got %+v", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Lease did not return after push")
+	}
+}
+
+func TestUniqueue_Lease_Errors(t *testing.T) {
+	u := NewUniqueue[int]()
+
+	if _, _, err := u.Lease(context.Background(), 0); !errors.Is(err, ErrInvalidTimeout) {
+		t.Errorf("Expected ErrInvalidTimeout, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := u.Lease(ctx, time.Second); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestUniqueue_Nack_OlderItemsScale(t *testing.T) {
+	for _, reverse := range []bool{false, true} {
+		assertLinear(t, 2000, func(n int) {
+			clock := newFakeClock()
+			u := NewUniqueue[int](withClock(clock.Now))
+			for i := range n {
+				u.PushBack(i)
+				clock.Advance(time.Millisecond)
+			}
+			ids := make([]LeaseID, n)
+			for i := range ids {
+				_, ids[i], _ = u.Lease(context.Background(), time.Minute)
+			}
+			u.PushBack(n)
+			if reverse {
+				slices.Reverse(ids)
+			}
+
+			for _, id := range ids {
+				u.Nack(id)
+			}
+			if err := uniqueueInvariants(u.uniqueue); err != nil {
+				t.Fatalf("Broken invariant: %v", err)
+			}
+			if age := u.OldestAge(); age != time.Duration(n)*time.Millisecond {
+				t.Errorf("Expected the requeued items to keep their age, got %v", age)
+			}
+		})
+	}
+}
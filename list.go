@@ -2,12 +2,30 @@
 // of a unique queue data structure with generic type support.
 package uniqueue
 
-// Queue is a generic doubly-linked list queue that supports FIFO operations.
+// List is a generic doubly-linked list queue that supports FIFO operations
+// on items of any type, including slices, maps and funcs.
 // It allows duplicate items and provides O(1) push/pop operations.
-type Queue[T comparable] struct {
+type List[T any] struct {
 	head   *node[T]
 	tail   *node[T]
 	length int
+
+	// free is a stack of detached nodes linked through next, kept for
+	// reuse by later pushes. It holds at most freeMax nodes.
+	free    *node[T]
+	freeLen int
+	freeMax int
+}
+
+// NewList creates and returns a new empty list.
+func NewList[T any]() *List[T] {
+	return &List[T]{}
+}
+
+// Queue is a List of comparable items. In addition to the List operations
+// it can look up and replace items by equality.
+type Queue[T comparable] struct {
+	List[T]
 }
 
 // NewQueue creates and returns a new empty queue.
@@ -15,7 +33,7 @@ func NewQueue[T comparable]() *Queue[T] {
 	return &Queue[T]{}
 }
 
-type node[T comparable] struct {
+type node[T any] struct {
 	value T
 	next  *node[T]
 	prev  *node[T]
@@ -23,44 +41,338 @@ type node[T comparable] struct {
 
 // PushBack adds an item to the end of the queue.
 // Time complexity: O(1)
-func (q *Queue[T]) PushBack(item T) {
-	node := &node[T]{value: item}
+func (q *List[T]) PushBack(item T) {
+	q.pushBack(item)
+}
+
+// pushBack appends item and returns the node holding it, so that callers
+// maintaining an index can later unlink it in O(1).
+func (q *List[T]) pushBack(item T) *node[T] {
+	node := q.alloc(item)
+	q.linkBack(node)
+	return node
+}
+
+// SetNodePool keeps up to n nodes of removed items for reuse, so that a
+// list whose size stays below its previous peak pushes and pops without
+// allocating. Zero, the default, disables pooling; lowering the limit
+// releases surplus pooled nodes.
+func (q *List[T]) SetNodePool(n int) {
+	q.freeMax = max(n, 0)
+	for q.freeLen > q.freeMax {
+		q.free = q.free.next
+		q.freeLen--
+	}
+}
+
+// alloc returns a detached node holding item, reusing a pooled node if
+// one is available.
+func (q *List[T]) alloc(item T) *node[T] {
+	n := q.free
+	if n == nil {
+		return &node[T]{value: item}
+	}
+	q.free = n.next
+	q.freeLen--
+	n.next = nil
+	n.value = item
+	return n
+}
+
+// release clears the value of the detached node n, so the list no longer
+// retains it, and pools n if there is room.
+func (q *List[T]) release(n *node[T]) {
+	var zero T
+	n.value = zero
+	if q.freeLen < q.freeMax {
+		n.next = q.free
+		q.free = n
+		q.freeLen++
+	}
+}
+
+// linkBack appends the detached node n.
+func (q *List[T]) linkBack(n *node[T]) {
+	if q.head == nil {
+		q.head = n
+		q.tail = n
+	} else {
+		q.tail.next = n
+		n.prev = q.tail
+		q.tail = n
+	}
+	q.length++
+}
+
+// pushFront prepends item and returns the node holding it.
+func (q *List[T]) pushFront(item T) *node[T] {
+	node := q.alloc(item)
 	if q.head == nil {
 		q.head = node
 		q.tail = node
 	} else {
-		q.tail.next = node
-		node.prev = q.tail
-		q.tail = node
+		q.head.prev = node
+		node.next = q.head
+		q.head = node
 	}
 	q.length++
+	return node
+}
+
+// unlink removes n from the queue. n must belong to q.
+func (q *List[T]) unlink(n *node[T]) {
+	if n.prev != nil {
+		n.prev.next = n.next
+	} else {
+		q.head = n.next
+	}
+	if n.next != nil {
+		n.next.prev = n.prev
+	} else {
+		q.tail = n.prev
+	}
+	n.next = nil
+	n.prev = nil
+	q.length--
 }
 
 // PopHead removes and returns the first item from the queue.
 // Returns the zero value and false if the queue is empty.
 // Time complexity: O(1)
-func (q *Queue[T]) PopHead() (T, bool) {
+func (q *List[T]) PopHead() (T, bool) {
 	var result T
 	if q.head == nil {
 		return result, false
 	}
 
 	node := q.head
-	q.head = node.next
-	if q.head != nil {
-		q.head.prev = nil
+	q.unlink(node)
+	result = node.value
+	q.release(node)
+	return result, true
+}
+
+// PopTail removes and returns the last item from the queue.
+// Returns the zero value and false if the queue is empty.
+// Time complexity: O(1)
+func (q *List[T]) PopTail() (T, bool) {
+	var result T
+	if q.tail == nil {
+		return result, false
+	}
+
+	node := q.tail
+	q.unlink(node)
+	result = node.value
+	q.release(node)
+	return result, true
+}
+
+// insertBefore links the detached node n in front of mark.
+func (q *List[T]) insertBefore(n, mark *node[T]) {
+	n.prev = mark.prev
+	n.next = mark
+	if mark.prev != nil {
+		mark.prev.next = n
 	} else {
-		q.tail = nil
+		q.head = n
 	}
-	q.length--
-	return node.value, true
+	mark.prev = n
+	q.length++
 }
 
-// Contains checks if an item exists in the queue.
-// Time complexity: O(n) where n is the queue length
-func (q *Queue[T]) Contains(item T) bool {
+// insertAfter links the detached node n behind mark.
+func (q *List[T]) insertAfter(n, mark *node[T]) {
+	n.prev = mark
+	n.next = mark.next
+	if mark.next != nil {
+		mark.next.prev = n
+	} else {
+		q.tail = n
+	}
+	mark.next = n
+	q.length++
+}
+
+// moveToFront moves n to the head of the queue. n must belong to q.
+func (q *List[T]) moveToFront(n *node[T]) {
+	if q.head == n {
+		return
+	}
+	q.unlink(n)
+	q.insertBefore(n, q.head)
+}
+
+// moveToBack moves n to the tail of the queue. n must belong to q.
+func (q *List[T]) moveToBack(n *node[T]) {
+	if q.tail == n {
+		return
+	}
+	q.unlink(n)
+	q.insertAfter(n, q.tail)
+}
+
+// moveBefore moves n in front of mark. Both must belong to q.
+func (q *List[T]) moveBefore(n, mark *node[T]) {
+	if n == mark || n.next == mark {
+		return
+	}
+	q.unlink(n)
+	q.insertBefore(n, mark)
+}
+
+// moveAfter moves n behind mark. Both must belong to q.
+func (q *List[T]) moveAfter(n, mark *node[T]) {
+	if n == mark || n.prev == mark {
+		return
+	}
+	q.unlink(n)
+	q.insertAfter(n, mark)
+}
+
+// Peek returns the first item without removing it.
+// Returns the zero value and false if the queue is empty.
+// Time complexity: O(1)
+func (q *List[T]) Peek() (T, bool) {
+	if q.head == nil {
+		var result T
+		return result, false
+	}
+	return q.head.value, true
+}
+
+// PeekTail returns the last item without removing it.
+// Returns the zero value and false if the queue is empty.
+// Time complexity: O(1)
+func (q *List[T]) PeekTail() (T, bool) {
+	if q.tail == nil {
+		var result T
+		return result, false
+	}
+	return q.tail.value, true
+}
+
+// PeekN returns up to n items from the head of the queue, in order,
+// without removing them.
+// Time complexity: O(n)
+func (q *List[T]) PeekN(n int) []T {
+	n = min(max(n, 0), q.length)
+	result := make([]T, 0, n)
+	for node := q.head; node != nil && len(result) < n; node = node.next {
+		result = append(result, node.value)
+	}
+	return result
+}
+
+// Clear removes all items from the queue.
+// Time complexity: O(1)
+func (q *List[T]) Clear() {
+	q.head = nil
+	q.tail = nil
+	q.length = 0
+}
+
+// Retain keeps only the items for which pred returns true, preserving
+// their order, and returns the number of items removed.
+// Time complexity: O(n)
+func (q *List[T]) Retain(pred func(T) bool) int {
+	return q.retain(pred, nil)
+}
+
+// retain is Retain with a callback invoked for every removed item.
+func (q *List[T]) retain(pred func(T) bool, removed func(T)) int {
+	n := 0
+	for node := q.head; node != nil; {
+		next := node.next
+		if !pred(node.value) {
+			q.unlink(node)
+			if removed != nil {
+				removed(node.value)
+			}
+			q.release(node)
+			n++
+		}
+		node = next
+	}
+	return n
+}
+
+// ReplaceFunc substitutes the first item for which match returns true with
+// new, keeping its position. Returns false if no item matches.
+// Time complexity: O(n)
+func (q *List[T]) ReplaceFunc(match func(T) bool, new T) bool {
+	for node := q.head; node != nil; node = node.next {
+		if match(node.value) {
+			node.value = new
+			return true
+		}
+	}
+	return false
+}
+
+// Concat moves all items of other to the end of q, leaving other empty.
+// Time complexity: O(1)
+func (q *List[T]) Concat(other *List[T]) {
+	if other == q || other.head == nil {
+		return
+	}
+	if q.head == nil {
+		q.head = other.head
+	} else {
+		q.tail.next = other.head
+		other.head.prev = q.tail
+	}
+	q.tail = other.tail
+	q.length += other.length
+	other.Clear()
+}
+
+// SplitAt cuts the queue after its first n items. q keeps the first n
+// items and the rest are returned, in order, as a new list.
+// If n is negative it is treated as zero; if it is at least the size of
+// the list the returned list is empty.
+// Time complexity: O(min(n, size-n))
+func (q *List[T]) SplitAt(n int) *List[T] {
+	rest := NewList[T]()
+	n = max(n, 0)
+	if n >= q.length {
+		return rest
+	}
+
+	// first is the node that becomes the head of rest.
+	var first *node[T]
+	if n <= q.length/2 {
+		first = q.head
+		for i := 0; i < n; i++ {
+			first = first.next
+		}
+	} else {
+		first = q.tail
+		for i := q.length - 1; i > n; i-- {
+			first = first.prev
+		}
+	}
+
+	rest.head = first
+	rest.tail = q.tail
+	rest.length = q.length - n
+
+	q.tail = first.prev
+	q.length = n
+	if q.tail == nil {
+		q.head = nil
+	} else {
+		q.tail.next = nil
+	}
+	first.prev = nil
+	return rest
+}
+
+// ContainsFunc reports whether eq returns true for any item in the list.
+// Time complexity: O(n) where n is the list length
+func (q *List[T]) ContainsFunc(eq func(T) bool) bool {
 	for node := q.head; node != nil; node = node.next {
-		if node.value == item {
+		if eq(node.value) {
 			return true
 		}
 	}
@@ -69,6 +381,38 @@ func (q *Queue[T]) Contains(item T) bool {
 
 // Size returns the number of items in the queue.
 // Time complexity: O(1)
-func (q *Queue[T]) Size() int {
+func (q *List[T]) Size() int {
 	return q.length
 }
+
+// IsEmpty returns true if the queue is empty, false otherwise.
+// Time complexity: O(1)
+func (q *List[T]) IsEmpty() bool {
+	return q.length == 0
+}
+
+// Contains checks if an item exists in the queue.
+// Time complexity: O(n) where n is the queue length
+func (q *Queue[T]) Contains(item T) bool {
+	return q.ContainsFunc(func(v T) bool { return v == item })
+}
+
+// Replace substitutes the first occurrence of old with new, keeping its
+// position. Returns false if old is not in the queue.
+// Time complexity: O(n)
+func (q *Queue[T]) Replace(old, new T) bool {
+	return q.ReplaceFunc(func(v T) bool { return v == old }, new)
+}
+
+// Concat moves all items of other to the end of q, leaving other empty.
+// Time complexity: O(1)
+func (q *Queue[T]) Concat(other *Queue[T]) {
+	q.List.Concat(&other.List)
+}
+
+// SplitAt cuts the queue after its first n items. q keeps the first n
+// items and the rest are returned, in order, as a new queue.
+// Time complexity: O(min(n, size-n))
+func (q *Queue[T]) SplitAt(n int) *Queue[T] {
+	return &Queue[T]{List: *q.List.SplitAt(n)}
+}
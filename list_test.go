@@ -1,6 +1,7 @@
 package uniqueue
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -120,6 +121,46 @@ func TestQueue_PopHead(t *testing.T) {
 	})
 }
 
+func TestQueue_PopTail(t *testing.T) {
+	q := NewQueue[int]()
+	if _, ok := q.PopTail(); ok {
+		t.Error("Expected PopTail on empty queue to return false")
+	}
+
+	q.PushBack(1)
+	q.PushBack(2)
+	q.PushBack(3)
+	if val, ok := q.PopTail(); !ok || val != 3 {
+		t.Errorf("Expected (3, true), got (%d, %v)", val, ok)
+	}
+	if got := queueValues(t, q); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Expected [1 2], got %v", got)
+	}
+
+	q.PopTail()
+	q.PopTail()
+	if q.Size() != 0 || q.head != nil || q.tail != nil {
+		t.Errorf("Expected empty queue, got size %d", q.Size())
+	}
+}
+
+func TestQueue_IsEmpty(t *testing.T) {
+	q := NewQueue[int]()
+	if !q.IsEmpty() {
+		t.Error("Expected new queue to be empty")
+	}
+
+	q.PushBack(1)
+	if q.IsEmpty() {
+		t.Error("Expected queue not to be empty after PushBack")
+	}
+
+	q.PopHead()
+	if !q.IsEmpty() {
+		t.Error("Expected queue to be empty after popping the last item")
+	}
+}
+
 func TestQueue_Contains(t *testing.T) {
 	t.Run("empty queue", func(t *testing.T) {
 		q := NewQueue[int]()
@@ -253,3 +294,340 @@ func TestQueue_GenericTypes(t *testing.T) {
 		}
 	})
 }
+
+func TestQueue_Peek(t *testing.T) {
+	q := NewQueue[int]()
+
+	if _, ok := q.Peek(); ok {
+		t.Error("Expected ok=false for empty queue")
+	}
+	if _, ok := q.PeekTail(); ok {
+		t.Error("Expected ok=false for empty queue")
+	}
+
+	q.PushBack(1)
+	q.PushBack(2)
+	q.PushBack(3)
+
+	if val, ok := q.Peek(); !ok || val != 1 {
+		t.Errorf("Expected (1, true), got (%d, %v)", val, ok)
+	}
+	if val, ok := q.PeekTail(); !ok || val != 3 {
+		t.Errorf("Expected (3, true), got (%d, %v)", val, ok)
+	}
+	if q.Size() != 3 {
+		t.Errorf("Expected Peek not to change size, got %d", q.Size())
+	}
+}
+
+func TestQueue_PeekN(t *testing.T) {
+	q := NewQueue[int]()
+	q.PushBack(1)
+	q.PushBack(2)
+	q.PushBack(3)
+
+	tests := []struct {
+		n        int
+		expected []int
+	}{
+		{-1, []int{}},
+		{0, []int{}},
+		{2, []int{1, 2}},
+		{3, []int{1, 2, 3}},
+		{10, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		if got := q.PeekN(tt.n); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("PeekN(%d): expected %v, got %v", tt.n, tt.expected, got)
+		}
+	}
+}
+
+func TestQueue_Clear(t *testing.T) {
+	q := NewQueue[int]()
+	q.PushBack(1)
+	q.PushBack(2)
+
+	q.Clear()
+	if q.Size() != 0 {
+		t.Errorf("Expected size 0, got %d", q.Size())
+	}
+	if _, ok := q.PopHead(); ok {
+		t.Error("Expected ok=false after Clear")
+	}
+
+	q.PushBack(3)
+	if val, ok := q.PopHead(); !ok || val != 3 {
+		t.Errorf("Expected (3, true), got (%d, %v)", val, ok)
+	}
+}
+
+func TestQueue_Retain(t *testing.T) {
+	q := NewQueue[int]()
+	for i := 1; i <= 6; i++ {
+		q.PushBack(i)
+	}
+
+	removed := q.Retain(func(v int) bool { return v%2 == 0 })
+	if removed != 3 {
+		t.Errorf("Expected 3 removed, got %d", removed)
+	}
+	if got := q.PeekN(10); !reflect.DeepEqual(got, []int{2, 4, 6}) {
+		t.Errorf("Expected [2 4 6], got %v", got)
+	}
+	if val, _ := q.PeekTail(); val != 6 {
+		t.Errorf("Expected tail 6, got %d", val)
+	}
+
+	if removed := q.Retain(func(int) bool { return false }); removed != 3 {
+		t.Errorf("Expected 3 removed, got %d", removed)
+	}
+	if q.Size() != 0 {
+		t.Errorf("Expected size 0, got %d", q.Size())
+	}
+}
+
+func TestQueue_Replace(t *testing.T) {
+	q := NewQueue[string]()
+	q.PushBack("a")
+	q.PushBack("b")
+	q.PushBack("b")
+
+	if !q.Replace("b", "x") {
+		t.Error("Expected Replace to return true")
+	}
+	if q.Replace("missing", "y") {
+		t.Error("Expected Replace to return false for missing item")
+	}
+	if got := q.PeekN(3); !reflect.DeepEqual(got, []string{"a", "x", "b"}) {
+		t.Errorf("Expected only the first occurrence to be replaced, got %v", got)
+	}
+}
+
+// queueValues returns the queue contents walking forward, after checking
+// that walking backward yields the same items.
+func queueValues[T comparable](t *testing.T, q *Queue[T]) []T {
+	t.Helper()
+	forward := q.PeekN(q.Size())
+	var backward []T
+	for node := q.tail; node != nil; node = node.prev {
+		backward = append([]T{node.value}, backward...)
+	}
+	if len(backward) != len(forward) || (len(forward) > 0 && !reflect.DeepEqual(forward, backward)) {
+		t.Errorf("Inconsistent links: forward %v, backward %v", forward, backward)
+	}
+	return forward
+}
+
+func TestQueue_Concat(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []int
+	}{
+		{"both non-empty", []int{1, 2}, []int{3, 4}},
+		{"empty receiver", nil, []int{3, 4}},
+		{"empty argument", []int{1, 2}, nil},
+		{"both empty", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := NewQueue[int](), NewQueue[int]()
+			for _, v := range tt.a {
+				a.PushBack(v)
+			}
+			for _, v := range tt.b {
+				b.PushBack(v)
+			}
+
+			a.Concat(b)
+
+			expected := append(append([]int{}, tt.a...), tt.b...)
+			if got := queueValues(t, a); !reflect.DeepEqual(got, expected) {
+				t.Errorf("Expected %v, got %v", expected, got)
+			}
+			if a.Size() != len(expected) {
+				t.Errorf("Expected size %d, got %d", len(expected), a.Size())
+			}
+			if b.Size() != 0 {
+				t.Errorf("Expected argument to be empty, got size %d", b.Size())
+			}
+			b.PushBack(9)
+			if a.Contains(9) {
+				t.Error("Expected argument to be independent after Concat")
+			}
+		})
+	}
+
+	t.Run("self", func(t *testing.T) {
+		q := NewQueue[int]()
+		q.PushBack(1)
+		q.Concat(q)
+		if got := queueValues(t, q); !reflect.DeepEqual(got, []int{1}) {
+			t.Errorf("Expected [1], got %v", got)
+		}
+	})
+}
+
+func TestQueue_SplitAt(t *testing.T) {
+	for n := -1; n <= 6; n++ {
+		q := NewQueue[int]()
+		for i := 0; i < 5; i++ {
+			q.PushBack(i)
+		}
+
+		rest := q.SplitAt(n)
+
+		cut := min(max(n, 0), 5)
+		expectedHead := []int{0, 1, 2, 3, 4}[:cut]
+		expectedRest := []int{0, 1, 2, 3, 4}[cut:]
+		if got := queueValues(t, q); !reflect.DeepEqual(got, expectedHead) {
+			t.Errorf("SplitAt(%d): expected head %v, got %v", n, expectedHead, got)
+		}
+		if got := queueValues(t, rest); !reflect.DeepEqual(got, expectedRest) {
+			t.Errorf("SplitAt(%d): expected rest %v, got %v", n, expectedRest, got)
+		}
+		if q.Size()+rest.Size() != 5 {
+			t.Errorf("SplitAt(%d): sizes %d+%d do not add up to 5", n, q.Size(), rest.Size())
+		}
+	}
+}
+
+func TestList_NonComparable(t *testing.T) {
+	l := NewList[[]int]()
+	l.PushBack([]int{1, 2})
+	l.PushBack([]int{3})
+	l.PushBack([]int{4, 5, 6})
+
+	if l.Size() != 3 {
+		t.Errorf("Expected size 3, got %d", l.Size())
+	}
+
+	hasLen := func(n int) func([]int) bool {
+		return func(v []int) bool { return len(v) == n }
+	}
+	if !l.ContainsFunc(hasLen(1)) {
+		t.Error("Expected ContainsFunc to find the single-element slice")
+	}
+	if l.ContainsFunc(hasLen(7)) {
+		t.Error("Expected ContainsFunc to return false")
+	}
+
+	if !l.ReplaceFunc(hasLen(1), []int{9, 9}) {
+		t.Error("Expected ReplaceFunc to return true")
+	}
+	if l.ReplaceFunc(hasLen(1), nil) {
+		t.Error("Expected ReplaceFunc to return false once nothing matches")
+	}
+
+	val, ok := l.PopHead()
+	if !ok || !reflect.DeepEqual(val, []int{1, 2}) {
+		t.Errorf("Expected ([1 2], true), got (%v, %v)", val, ok)
+	}
+	if got := l.PeekN(2); !reflect.DeepEqual(got, [][]int{{9, 9}, {4, 5, 6}}) {
+		t.Errorf("Expected [[9 9] [4 5 6]], got %v", got)
+	}
+}
+
+func TestList_Funcs(t *testing.T) {
+	l := NewList[func() int]()
+	for i := 1; i <= 3; i++ {
+		l.PushBack(func() int { return i })
+	}
+
+	rest := l.SplitAt(1)
+	other := NewList[func() int]()
+	other.Concat(rest)
+	other.Concat(l)
+
+	var got []int
+	for {
+		fn, ok := other.PopHead()
+		if !ok {
+			break
+		}
+		got = append(got, fn())
+	}
+	if !reflect.DeepEqual(got, []int{2, 3, 1}) {
+		t.Errorf("Expected [2 3 1], got %v", got)
+	}
+}
+
+func TestList_NodePool(t *testing.T) {
+	l := NewList[int]()
+	l.SetNodePool(4)
+
+	for i := 0; i < 8; i++ {
+		l.PushBack(i)
+	}
+	for i := 0; i < 8; i++ {
+		l.PopHead()
+	}
+	if l.freeLen != 4 {
+		t.Errorf("Expected pool to be bounded at 4 nodes, got %d", l.freeLen)
+	}
+	for n := l.free; n != nil; n = n.next {
+		if n.value != 0 {
+			t.Errorf("Expected pooled node value to be cleared, got %d", n.value)
+		}
+		if n.prev != nil {
+			t.Error("Expected pooled node to be detached")
+		}
+	}
+
+	// Reused nodes behave like fresh ones.
+	l.PushBack(10)
+	l.PushBack(11)
+	if got := l.PeekN(3); !reflect.DeepEqual(got, []int{10, 11}) {
+		t.Errorf("Expected [10 11], got %v", got)
+	}
+
+	l.SetNodePool(1)
+	if l.freeLen != 1 {
+		t.Errorf("Expected lowering the limit to trim the pool, got %d", l.freeLen)
+	}
+}
+
+func TestList_PopClearsValue(t *testing.T) {
+	l := NewList[*int]()
+	v := 42
+	l.PushBack(&v)
+	l.PushBack(nil)
+
+	node := l.head
+	l.PopHead()
+	if node.value != nil {
+		t.Error("Expected popped node not to retain its value")
+	}
+}
+
+func TestList_NodePool_Allocs(t *testing.T) {
+	q := NewQueue[int]()
+	q.SetNodePool(16)
+	for i := 0; i < 16; i++ {
+		q.PushBack(i)
+	}
+	for i := 0; i < 16; i++ {
+		q.PopHead()
+	}
+
+	allocs := testing.AllocsPerRun(1000, func() {
+		for i := 0; i < 16; i++ {
+			q.PushBack(i)
+		}
+		for i := 0; i < 16; i++ {
+			q.PopHead()
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Expected steady-state push/pop not to allocate, got %v allocs per run", allocs)
+	}
+
+	unpooled := NewQueue[int]()
+	allocs = testing.AllocsPerRun(100, func() {
+		unpooled.PushBack(1)
+		unpooled.PopHead()
+	})
+	if allocs != 1 {
+		t.Errorf("Expected one allocation per push without a pool, got %v", allocs)
+	}
+}
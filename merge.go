@@ -0,0 +1,92 @@
+package uniqueue
+
+// MergePolicy decides what UniqueueUnsafe.Merge does with an item that is
+// present in both queues.
+type MergePolicy int
+
+const (
+	// MergeSkipDuplicates keeps the item at its current position and
+	// discards the copy from the merged queue.
+	MergeSkipDuplicates MergePolicy = iota
+	// MergeRelocateDuplicates moves the item to the position it has in the
+	// merged queue, behind the items merged before it.
+	MergeRelocateDuplicates
+)
+
+// Merge moves all items of other to the end of u in their original order,
+// leaving other empty. Items already in u are handled according to policy.
+// Returns how many duplicates were collapsed.
+//
+// Nodes are relinked rather than copied, and items keep the earliest of
+// their enqueue timestamps. Observers of u are notified as if the items of
+// other were pushed; observers of other are not notified.
+// Time complexity: O(m) where m is the size of other, plus O(n) where n is
+// the size of u if other holds items older than those of u
+func (u *UniqueueUnsafe[T]) Merge(other *UniqueueUnsafe[T], policy MergePolicy) int {
+	if other == u {
+		return 0
+	}
+
+	var zero T
+	if other.queue.head != nil {
+		other.record(ChangeClear, zero, zero)
+	}
+	collapsed := 0
+	for node := other.queue.head; node != nil; {
+		next := node.next
+		item := node.value
+		incoming := other.seen[item]
+		other.queue.unlink(node)
+
+		if existing, ok := u.seen[item]; ok {
+			collapsed++
+			if policy == MergeRelocateDuplicates {
+				u.record(ChangeMoveToBack, item, zero)
+				u.queue.moveToBack(existing.node)
+			}
+			if incoming.enqueued.Before(existing.enqueued) {
+				u.removeByAge(existing)
+				existing.enqueued = incoming.enqueued
+				existing.age = nil
+				u.seen[item] = existing
+			}
+			other.queue.release(node)
+			u.duplicate(item)
+		} else {
+			u.record(ChangePushBack, item, zero)
+			u.queue.linkBack(node)
+			u.seen[item] = entry[T]{
+				node:     node,
+				enqueued: incoming.enqueued,
+				seq:      u.seq,
+			}
+			u.stats.Adds++
+			if u.metrics != nil {
+				u.metrics.Add()
+			}
+			u.notify(eventPush, item)
+		}
+		node = next
+	}
+
+	// Place the items without an age node in one pass over both age
+	// orders: taken oldest first, each is found forward of the last.
+	for node := other.byAge.head; node != nil; node = node.next {
+		if e := u.seen[node.value]; e.age == nil {
+			e.age = u.insertByAge(node.value, e.enqueued)
+			u.seen[node.value] = e
+		}
+	}
+	other.byAge.Clear()
+	other.ageHint = nil
+	other.seen = make(map[T]entry[T], other.sizeHint)
+	other.peak = 0
+	u.peak = max(u.peak, len(u.seen))
+	if u.metrics != nil {
+		u.reportDepth(u.now())
+	}
+	if other.metrics != nil {
+		other.reportDepth(other.now())
+	}
+	return collapsed
+}
@@ -0,0 +1,115 @@
+package uniqueue
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUniqueueUnsafe_Merge(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    MergePolicy
+		expected  []int
+		collapsed int
+	}{
+		{"skip duplicates", MergeSkipDuplicates, []int{1, 2, 3, 4, 5}, 2},
+		{"relocate duplicates", MergeRelocateDuplicates, []int{1, 4, 2, 5, 3}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newUniqueueUnsafeOf(1, 2, 3)
+			other := newUniqueueUnsafeOf(4, 2, 5, 3)
+
+			if collapsed := u.Merge(other, tt.policy); collapsed != tt.collapsed {
+				t.Errorf("Expected %d collapsed, got %d", tt.collapsed, collapsed)
+			}
+			assertOrder(t, u, tt.expected)
+			assertOrder(t, other, []int{})
+			for _, item := range tt.expected {
+				if !u.Contains(item) {
+					t.Errorf("Expected Contains(%d) after merge", item)
+				}
+				if other.Contains(item) {
+					t.Errorf("Expected other not to contain %d after merge", item)
+				}
+			}
+
+			// Both queues remain usable on their own.
+			other.PushBack(1)
+			u.PushBack(6)
+			assertOrder(t, other, []int{1})
+			assertOrder(t, u, append(tt.expected, 6))
+		})
+	}
+}
+
+func TestUniqueueUnsafe_Merge_Timestamps(t *testing.T) {
+	clock := newFakeClock()
+	u := NewUniqueueUnsafe[string](withClock(clock.Now))
+	other := NewUniqueueUnsafe[string](withClock(clock.Now))
+
+	other.PushBack("a")
+	clock.Advance(time.Second)
+	u.PushBack("a")
+	clock.Advance(time.Second)
+
+	u.Merge(other, MergeSkipDuplicates)
+	if age := u.OldestAge(); age != 2*time.Second {
+		t.Errorf("Expected merged item to keep the earliest timestamp (2s), got %v", age)
+	}
+}
+
+func TestUniqueueUnsafe_Merge_Observer(t *testing.T) {
+	obs := &recordingObserver{}
+	u := NewUniqueueUnsafe[int](WithObserver[int](obs))
+	u.PushBack(1)
+
+	u.Merge(newUniqueueUnsafeOf(1, 2), MergeSkipDuplicates)
+
+	expected := []string{"push:1", "duplicate:1", "push:2"}
+	if got := obs.Events(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected events %v, got %v", expected, got)
+	}
+	if u.Merge(u, MergeSkipDuplicates) != 0 {
+		t.Error("Expected merging a queue into itself to do nothing")
+	}
+	assertOrder(t, u, []int{1, 2})
+}
+
+// assertLinear fails if run(8n) takes far longer than 8 times run(n),
+// which a quadratic run would.
+func assertLinear(t *testing.T, n int, run func(n int)) {
+	t.Helper()
+	measure := func(n int) time.Duration {
+		start := time.Now()
+		run(n)
+		return time.Since(start)
+	}
+	small, large := measure(n), measure(8*n)
+	if large > 24*small+50*time.Millisecond {
+		t.Errorf("Expected linear scaling, %d items took %v but %d took %v", n, small, 8*n, large)
+	}
+}
+
+func TestUniqueueUnsafe_Merge_OlderItemsScale(t *testing.T) {
+	assertLinear(t, 5000, func(n int) {
+		clock := newFakeClock()
+		u := NewUniqueueUnsafe[int](withClock(clock.Now))
+		other := NewUniqueueUnsafe[int](withClock(clock.Now))
+		for i := range n {
+			other.PushBack(i)
+			clock.Advance(time.Millisecond)
+		}
+		u.PushBack(n)
+		clock.Advance(time.Second)
+
+		u.Merge(other, MergeSkipDuplicates)
+		if err := uniqueueInvariants(u); err != nil {
+			t.Fatalf("Broken invariant: %v", err)
+		}
+		if age := u.OldestAge(); age != time.Duration(n)*time.Millisecond+time.Second {
+			t.Errorf("Expected the oldest merged item to set the age, got %v", age)
+		}
+	})
+}
@@ -0,0 +1,219 @@
+package uniqueue
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics receives measurements from a queue configured with WithMetrics.
+// Methods are called while the queue's lock is held, so they must be cheap
+// and must not call back into the queue. An implementation shared between
+// several queues must be safe for concurrent use.
+type Metrics interface {
+	// Add is called when a new item is enqueued.
+	Add()
+	// Duplicate is called when a push is rejected because the item is
+	// already queued.
+	Duplicate()
+	// Pop is called when an item is popped from either end of the queue,
+	// with the time it spent queued.
+	Pop(latency time.Duration)
+	// Depth is called with the number of queued items after every change.
+	Depth(n int)
+	// UnfinishedWork is called with the age of the oldest queued item
+	// after every change, or zero if the queue is empty.
+	UnfinishedWork(oldest time.Duration)
+}
+
+// DefaultLatencyBuckets are the histogram upper bounds, in seconds, used by
+// NewInMemoryMetrics when no buckets are given.
+var DefaultLatencyBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 60}
+
+// InMemoryMetrics is a Metrics implementation that keeps counters, gauges
+// and a time-in-queue histogram in memory. It is safe for concurrent use.
+type InMemoryMetrics struct {
+	name string
+
+	mu         sync.Mutex
+	adds       uint64
+	duplicates uint64
+	pops       uint64
+	depth      int
+	unfinished time.Duration
+	buckets    []float64
+	counts     []uint64
+	sum        float64
+}
+
+// MetricsSnapshot is a point-in-time copy of InMemoryMetrics.
+type MetricsSnapshot struct {
+	Adds       uint64
+	Duplicates uint64
+	Pops       uint64
+	Depth      int
+	Unfinished time.Duration
+	// Buckets holds the histogram upper bounds in seconds and Counts the
+	// number of observations less than or equal to each bound.
+	Buckets []float64
+	Counts  []uint64
+	// LatencySum is the total time-in-queue of all popped items.
+	LatencySum time.Duration
+}
+
+// NewInMemoryMetrics creates metrics labelled with name. buckets are the
+// histogram upper bounds in seconds; DefaultLatencyBuckets is used when
+// none are given.
+func NewInMemoryMetrics(name string, buckets ...float64) *InMemoryMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &InMemoryMetrics{
+		name:    name,
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+// Name returns the label value the metrics were created with.
+func (m *InMemoryMetrics) Name() string {
+	return m.name
+}
+
+// Add implements Metrics.
+func (m *InMemoryMetrics) Add() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.adds++
+}
+
+// Duplicate implements Metrics.
+func (m *InMemoryMetrics) Duplicate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.duplicates++
+}
+
+// Pop implements Metrics.
+func (m *InMemoryMetrics) Pop(latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pops++
+	seconds := latency.Seconds()
+	m.sum += seconds
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			m.counts[i]++
+		}
+	}
+}
+
+// Depth implements Metrics.
+func (m *InMemoryMetrics) Depth(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.depth = n
+}
+
+// UnfinishedWork implements Metrics.
+func (m *InMemoryMetrics) UnfinishedWork(oldest time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.unfinished = oldest
+}
+
+// Snapshot returns a copy of the current values.
+func (m *InMemoryMetrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return MetricsSnapshot{
+		Adds:       m.adds,
+		Duplicates: m.duplicates,
+		Pops:       m.pops,
+		Depth:      m.depth,
+		Unfinished: m.unfinished,
+		Buckets:    append([]float64(nil), m.buckets...),
+		Counts:     append([]uint64(nil), m.counts...),
+		LatencySum: time.Duration(m.sum * float64(time.Second)),
+	}
+}
+
+// WritePrometheus writes m in the Prometheus text exposition format.
+func (m *InMemoryMetrics) WritePrometheus(w io.Writer) error {
+	return WritePrometheus(w, m)
+}
+
+// WritePrometheus writes the given metrics in the Prometheus text
+// exposition format. Each metric family is written once, with one series
+// per InMemoryMetrics distinguished by the "name" label.
+func WritePrometheus(w io.Writer, metrics ...*InMemoryMetrics) error {
+	snapshots := make([]MetricsSnapshot, len(metrics))
+	labels := make([]string, len(metrics))
+	for i, m := range metrics {
+		snapshots[i] = m.Snapshot()
+		labels[i] = `name="` + escapeLabel(m.name) + `"`
+	}
+
+	bw := bufio.NewWriter(w)
+	family := func(name, kind, help string) {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+
+	family("uniqueue_depth", "gauge", "Current number of items in the queue.")
+	for i, s := range snapshots {
+		fmt.Fprintf(bw, "uniqueue_depth{%s} %d\n", labels[i], s.Depth)
+	}
+	family("uniqueue_adds_total", "counter", "Total number of items added to the queue.")
+	for i, s := range snapshots {
+		fmt.Fprintf(bw, "uniqueue_adds_total{%s} %d\n", labels[i], s.Adds)
+	}
+	family("uniqueue_duplicates_total", "counter", "Total number of pushes rejected as duplicates.")
+	for i, s := range snapshots {
+		fmt.Fprintf(bw, "uniqueue_duplicates_total{%s} %d\n", labels[i], s.Duplicates)
+	}
+	family("uniqueue_pops_total", "counter", "Total number of items popped from the queue.")
+	for i, s := range snapshots {
+		fmt.Fprintf(bw, "uniqueue_pops_total{%s} %d\n", labels[i], s.Pops)
+	}
+	family("uniqueue_unfinished_work_seconds", "gauge", "Age of the oldest item in the queue.")
+	for i, s := range snapshots {
+		fmt.Fprintf(bw, "uniqueue_unfinished_work_seconds{%s} %s\n", labels[i], formatFloat(s.Unfinished.Seconds()))
+	}
+	family("uniqueue_queue_duration_seconds", "histogram", "Time items spent in the queue before being popped.")
+	for i, s := range snapshots {
+		for j, bound := range s.Buckets {
+			fmt.Fprintf(bw, "uniqueue_queue_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels[i], formatFloat(bound), s.Counts[j])
+		}
+		fmt.Fprintf(bw, "uniqueue_queue_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels[i], s.Pops)
+		fmt.Fprintf(bw, "uniqueue_queue_duration_seconds_sum{%s} %s\n", labels[i], formatFloat(s.LatencySum.Seconds()))
+		fmt.Fprintf(bw, "uniqueue_queue_duration_seconds_count{%s} %d\n", labels[i], s.Pops)
+	}
+	return bw.Flush()
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -0,0 +1,201 @@
+package uniqueue
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time          { return c.t }
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestUniqueue_Metrics(t *testing.T) {
+	clock := newFakeClock()
+	m := NewInMemoryMetrics("jobs")
+	u := NewUniqueue[string](WithMetrics(m), withClock(clock.Now))
+
+	u.PushBack("a")
+	clock.Advance(2 * time.Second)
+	u.PushBack("b")
+	u.PushBack("a") // duplicate
+	clock.Advance(3 * time.Second)
+
+	s := m.Snapshot()
+	if s.Adds != 2 {
+		t.Errorf("Expected 2 adds, got %d", s.Adds)
+	}
+	if s.Duplicates != 1 {
+		t.Errorf("Expected 1 duplicate, got %d", s.Duplicates)
+	}
+	if s.Depth != 2 {
+		t.Errorf("Expected depth 2, got %d", s.Depth)
+	}
+	if s.Unfinished != 2*time.Second {
+		t.Errorf("Expected unfinished work 2s as of the last change, got %v", s.Unfinished)
+	}
+
+	u.UpdateMetrics()
+	if got := m.Snapshot().Unfinished; got != 5*time.Second {
+		t.Errorf("Expected unfinished work 5s after UpdateMetrics, got %v", got)
+	}
+	if got := u.OldestAge(); got != 5*time.Second {
+		t.Errorf("Expected oldest age 5s, got %v", got)
+	}
+
+	u.PopHead() // "a" waited 5s
+	u.Remove("b")
+
+	s = m.Snapshot()
+	if s.Pops != 1 {
+		t.Errorf("Expected 1 pop, got %d", s.Pops)
+	}
+	if s.Depth != 0 {
+		t.Errorf("Expected depth 0, got %d", s.Depth)
+	}
+	if s.Unfinished != 0 {
+		t.Errorf("Expected unfinished work 0 for empty queue, got %v", s.Unfinished)
+	}
+	if s.LatencySum != 5*time.Second {
+		t.Errorf("Expected latency sum 5s, got %v", s.LatencySum)
+	}
+	for i, bound := range s.Buckets {
+		expected := uint64(0)
+		if bound >= 5 {
+			expected = 1
+		}
+		if s.Counts[i] != expected {
+			t.Errorf("Expected bucket le=%v to count %d, got %d", bound, expected, s.Counts[i])
+		}
+	}
+}
+
+func TestUniqueueUnsafe_OldestAge(t *testing.T) {
+	clock := newFakeClock()
+	u := NewUniqueueUnsafe[int](withClock(clock.Now))
+
+	if u.OldestAge() != 0 {
+		t.Errorf("Expected 0 for empty queue, got %v", u.OldestAge())
+	}
+
+	u.PushBack(1)
+	clock.Advance(time.Second)
+	u.PushBack(2)
+	clock.Advance(time.Second)
+
+	if u.OldestAge() != 2*time.Second {
+		t.Errorf("Expected 2s, got %v", u.OldestAge())
+	}
+	u.PopHead()
+	if u.OldestAge() != time.Second {
+		t.Errorf("Expected 1s, got %v", u.OldestAge())
+	}
+}
+
+func TestUniqueueUnsafe_OldestAgeNotAtHead(t *testing.T) {
+	clock := newFakeClock()
+	u := NewUniqueueUnsafe[int](withClock(clock.Now))
+
+	u.PushBack(1)
+	clock.Advance(time.Second)
+	u.PushBack(2)
+	clock.Advance(time.Second)
+	u.PushFront(3)
+	u.MoveToFront(2)
+	clock.Advance(time.Second)
+
+	if got := u.OldestAge(); got != 3*time.Second {
+		t.Errorf("Expected 3s for the item behind the head, got %v", got)
+	}
+	u.Remove(1)
+	if got := u.OldestAge(); got != 2*time.Second {
+		t.Errorf("Expected 2s, got %v", got)
+	}
+	u.PopHead()
+	if got := u.OldestAge(); got != time.Second {
+		t.Errorf("Expected 1s, got %v", got)
+	}
+}
+
+func TestUniqueue_OldestAgeAfterNack(t *testing.T) {
+	clock := newFakeClock()
+	m := NewInMemoryMetrics("jobs")
+	u := NewUniqueue[string](WithMetrics(m), withClock(clock.Now))
+
+	u.PushBack("a")
+	clock.Advance(time.Second)
+	u.PushBack("b")
+	clock.Advance(time.Second)
+
+	_, id, err := u.Lease(t.Context(), time.Minute)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	clock.Advance(time.Second)
+	if got := u.OldestAge(); got != 2*time.Second {
+		t.Errorf("Expected 2s while a is leased, got %v", got)
+	}
+	if err := u.Nack(id); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := u.OldestAge(); got != 3*time.Second {
+		t.Errorf("Expected the requeued item to keep its age of 3s, got %v", got)
+	}
+	if got := m.Snapshot().Unfinished; got != 3*time.Second {
+		t.Errorf("Expected unfinished work 3s, got %v", got)
+	}
+
+	u.PopHead()
+	u.PushFront("c")
+	clock.Advance(time.Second)
+	if got := u.OldestAge(); got != 3*time.Second {
+		t.Errorf("Expected 3s for b behind the pushed front, got %v", got)
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	a := NewInMemoryMetrics("a", 1, 10)
+	b := NewInMemoryMetrics(`b"q`, 1, 10)
+	a.Add()
+	a.Add()
+	a.Duplicate()
+	a.Depth(1)
+	a.Pop(500 * time.Millisecond)
+	a.UnfinishedWork(1500 * time.Millisecond)
+
+	var sb strings.Builder
+	if err := WritePrometheus(&sb, a, b); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+	out := sb.String()
+
+	for _, line := range []string{
+		"# TYPE uniqueue_depth gauge",
+		`uniqueue_depth{name="a"} 1`,
+		`uniqueue_adds_total{name="a"} 2`,
+		`uniqueue_duplicates_total{name="a"} 1`,
+		`uniqueue_pops_total{name="a"} 1`,
+		`uniqueue_unfinished_work_seconds{name="a"} 1.5`,
+		"# TYPE uniqueue_queue_duration_seconds histogram",
+		`uniqueue_queue_duration_seconds_bucket{name="a",le="1"} 1`,
+		`uniqueue_queue_duration_seconds_bucket{name="a",le="10"} 1`,
+		`uniqueue_queue_duration_seconds_bucket{name="a",le="+Inf"} 1`,
+		`uniqueue_queue_duration_seconds_sum{name="a"} 0.5`,
+		`uniqueue_queue_duration_seconds_count{name="a"} 1`,
+		`uniqueue_depth{name="b\"q"} 0`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, out)
+		}
+	}
+	if n := strings.Count(out, "# TYPE uniqueue_depth "); n != 1 {
+		t.Errorf("Expected each family to be declared once, got %d declarations", n)
+	}
+}
@@ -0,0 +1,196 @@
+package uniqueue
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Observer receives notifications about queue lifecycle events.
+//
+// Observers registered with WithObserver are invoked synchronously on the
+// goroutine performing the operation, after the mutation has been applied.
+// For Uniqueue this means callbacks run while the queue's lock is held:
+// events are delivered in exactly the order the mutations happened, but a
+// callback must not call back into the same queue (it would deadlock) and
+// should return quickly since it delays every other caller.
+// Wrap a slow observer with NewAsyncObserver to dispatch events from a
+// separate goroutine instead.
+type Observer[T comparable] interface {
+	// OnPush is called when item is added to the queue at either end.
+	OnPush(item T)
+	// OnDuplicate is called when a push is ignored because item is
+	// already in the queue.
+	OnDuplicate(item T)
+	// OnPop is called when item is popped from either end of the queue.
+	OnPop(item T)
+	// OnRemove is called when item is removed explicitly with Remove or
+	// substituted by another item with Replace.
+	OnRemove(item T)
+	// OnDrop is called when item is discarded without being popped or
+	// removed individually, for example by Clear or Retain.
+	OnDrop(item T)
+}
+
+// ObserverFuncs adapts a set of optional functions to the Observer
+// interface. Nil fields are skipped.
+type ObserverFuncs[T comparable] struct {
+	Push      func(item T)
+	Duplicate func(item T)
+	Pop       func(item T)
+	Remove    func(item T)
+	Drop      func(item T)
+}
+
+// OnPush calls f.Push if it is set.
+func (f ObserverFuncs[T]) OnPush(item T) {
+	if f.Push != nil {
+		f.Push(item)
+	}
+}
+
+// OnDuplicate calls f.Duplicate if it is set.
+func (f ObserverFuncs[T]) OnDuplicate(item T) {
+	if f.Duplicate != nil {
+		f.Duplicate(item)
+	}
+}
+
+// OnPop calls f.Pop if it is set.
+func (f ObserverFuncs[T]) OnPop(item T) {
+	if f.Pop != nil {
+		f.Pop(item)
+	}
+}
+
+// OnRemove calls f.Remove if it is set.
+func (f ObserverFuncs[T]) OnRemove(item T) {
+	if f.Remove != nil {
+		f.Remove(item)
+	}
+}
+
+// OnDrop calls f.Drop if it is set.
+func (f ObserverFuncs[T]) OnDrop(item T) {
+	if f.Drop != nil {
+		f.Drop(item)
+	}
+}
+
+type eventKind uint8
+
+const (
+	eventPush eventKind = iota
+	eventDuplicate
+	eventPop
+	eventRemove
+	eventDrop
+)
+
+type event[T comparable] struct {
+	kind eventKind
+	item T
+}
+
+func (e event[T]) dispatch(obs Observer[T]) {
+	switch e.kind {
+	case eventPush:
+		obs.OnPush(e.item)
+	case eventDuplicate:
+		obs.OnDuplicate(e.item)
+	case eventPop:
+		obs.OnPop(e.item)
+	case eventRemove:
+		obs.OnRemove(e.item)
+	case eventDrop:
+		obs.OnDrop(e.item)
+	}
+}
+
+// AsyncObserver forwards events to another Observer from a dedicated
+// goroutine. Its callbacks never block: when the buffer is full the event
+// is discarded and counted in Dropped.
+//
+// Events are delivered in the order they were accepted. Close must be
+// called to release the dispatch goroutine.
+type AsyncObserver[T comparable] struct {
+	obs     Observer[T]
+	events  chan event[T]
+	done    chan struct{}
+	dropped atomic.Uint64
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAsyncObserver starts a goroutine that delivers events to obs and
+// returns an Observer that enqueues events for it. buffer is the number of
+// events that may be pending before new ones are discarded.
+func NewAsyncObserver[T comparable](obs Observer[T], buffer int) *AsyncObserver[T] {
+	if buffer < 0 {
+		buffer = 0
+	}
+	a := &AsyncObserver[T]{
+		obs:    obs,
+		events: make(chan event[T], buffer),
+		done:   make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *AsyncObserver[T]) run() {
+	defer close(a.done)
+	for e := range a.events {
+		e.dispatch(a.obs)
+	}
+}
+
+func (a *AsyncObserver[T]) send(kind eventKind, item T) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		a.dropped.Add(1)
+		return
+	}
+	select {
+	case a.events <- event[T]{kind: kind, item: item}:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+// OnPush enqueues a push event.
+func (a *AsyncObserver[T]) OnPush(item T) { a.send(eventPush, item) }
+
+// OnDuplicate enqueues a duplicate event.
+func (a *AsyncObserver[T]) OnDuplicate(item T) { a.send(eventDuplicate, item) }
+
+// OnPop enqueues a pop event.
+func (a *AsyncObserver[T]) OnPop(item T) { a.send(eventPop, item) }
+
+// OnRemove enqueues a remove event.
+func (a *AsyncObserver[T]) OnRemove(item T) { a.send(eventRemove, item) }
+
+// OnDrop enqueues a drop event.
+func (a *AsyncObserver[T]) OnDrop(item T) { a.send(eventDrop, item) }
+
+// Dropped returns the number of events discarded because the buffer was
+// full or the observer was closed.
+func (a *AsyncObserver[T]) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Close stops accepting events, waits until all pending events have been
+// delivered and then stops the dispatch goroutine. It is safe to call Close
+// more than once.
+func (a *AsyncObserver[T]) Close() {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.events)
+	}
+	a.mu.Unlock()
+
+	<-a.done
+}
@@ -0,0 +1,147 @@
+package uniqueue
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recordingObserver) record(kind string, item int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, fmt.Sprintf("%s:%d", kind, item))
+}
+
+func (r *recordingObserver) OnPush(item int)      { r.record("push", item) }
+func (r *recordingObserver) OnDuplicate(item int) { r.record("duplicate", item) }
+func (r *recordingObserver) OnPop(item int)       { r.record("pop", item) }
+func (r *recordingObserver) OnRemove(item int)    { r.record("remove", item) }
+func (r *recordingObserver) OnDrop(item int)      { r.record("drop", item) }
+
+func (r *recordingObserver) Events() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+func TestUniqueueUnsafe_Observer(t *testing.T) {
+	obs := &recordingObserver{}
+	u := NewUniqueueUnsafe[int](WithObserver[int](obs))
+
+	u.PushBack(1)
+	u.PushBack(2)
+	u.PushBack(1)
+	u.PopHead()
+	u.Remove(2)
+	u.Remove(3)
+	u.PopHead()
+
+	expected := []string{"push:1", "push:2", "duplicate:1", "pop:1", "remove:2"}
+	if got := obs.Events(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected events %v, got %v", expected, got)
+	}
+}
+
+func TestUniqueue_Observer(t *testing.T) {
+	first := &recordingObserver{}
+	second := &recordingObserver{}
+	u := NewUniqueue[int](WithObserver[int](first), WithObserver[int](second))
+
+	u.PushBack(1)
+	u.PushBack(1)
+	u.PopHead()
+
+	expected := []string{"push:1", "duplicate:1", "pop:1"}
+	if got := first.Events(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected events %v, got %v", expected, got)
+	}
+	if got := second.Events(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected events %v, got %v", expected, got)
+	}
+}
+
+func TestObserverFuncs(t *testing.T) {
+	var pushed []string
+	u := NewUniqueueUnsafe[string](WithObserver[string](ObserverFuncs[string]{
+		Push: func(item string) { pushed = append(pushed, item) },
+	}))
+
+	u.PushBack("a")
+	u.PushBack("a")
+	u.PopHead()
+
+	if !reflect.DeepEqual(pushed, []string{"a"}) {
+		t.Errorf("Expected [a], got %v", pushed)
+	}
+}
+
+func TestWithObserver_TypeMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for observer of a different item type")
+		}
+	}()
+	NewUniqueue[string](WithObserver[int](&recordingObserver{}))
+}
+
+func TestAsyncObserver(t *testing.T) {
+	obs := &recordingObserver{}
+	async := NewAsyncObserver[int](obs, 16)
+	u := NewUniqueue[int](WithObserver[int](async))
+
+	u.PushBack(1)
+	u.PushBack(2)
+	u.PopHead()
+	async.Close()
+
+	expected := []string{"push:1", "push:2", "pop:1"}
+	if got := obs.Events(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected events %v, got %v", expected, got)
+	}
+	if async.Dropped() != 0 {
+		t.Errorf("Expected no dropped events, got %d", async.Dropped())
+	}
+
+	// Events after Close are discarded rather than blocking or panicking.
+	u.PushBack(3)
+	if async.Dropped() != 1 {
+		t.Errorf("Expected 1 dropped event, got %d", async.Dropped())
+	}
+	async.Close()
+}
+
+func TestAsyncObserver_NonBlocking(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var once sync.Once
+	blocking := ObserverFuncs[int]{
+		Push: func(int) {
+			once.Do(func() { close(started) })
+			<-release
+		},
+	}
+	async := NewAsyncObserver[int](blocking, 1)
+	u := NewUniqueueUnsafe[int](WithObserver[int](async))
+
+	u.PushBack(1) // picked up by the dispatcher, which then blocks
+	<-started
+	u.PushBack(2) // fills the buffer
+	u.PushBack(3) // discarded
+	u.PushBack(4) // discarded
+
+	if async.Dropped() != 2 {
+		t.Errorf("Expected 2 dropped events, got %d", async.Dropped())
+	}
+	if u.Size() != 4 {
+		t.Errorf("Expected size 4, got %d", u.Size())
+	}
+
+	close(release)
+	async.Close()
+}
@@ -0,0 +1,240 @@
+package uniqueue
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidOption is wrapped by the errors returned for invalid options
+// or option combinations.
+var ErrInvalidOption = errors.New("uniqueue: invalid option")
+
+// Option configures a queue created by NewUniqueue or NewUniqueueUnsafe.
+type Option func(*options) error
+
+type options struct {
+	observers []any
+	metrics   Metrics
+	now       func() time.Time
+	requeue   RequeuePosition
+	// maxDeliveries is the number of leases after which an item is
+	// dead-lettered; zero means unlimited.
+	maxDeliveries int
+	nodePool      int
+	sizeHint      int
+	autoCompact   int
+	debounce      time.Duration
+	throttle      time.Duration
+	changeLog     int
+	// uniqueueOption names the last given option that only applies to
+	// Uniqueue, so that UniqueueUnsafe can reject it.
+	uniqueueOption string
+}
+
+func newOptions(opts []Option) (*options, error) {
+	o := &options{now: time.Now, requeue: RequeueHead}
+	for _, opt := range opts {
+		if opt == nil {
+			return nil, fmt.Errorf("%w: nil Option", ErrInvalidOption)
+		}
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+	if o.debounce > 0 && o.throttle > 0 {
+		return nil, invalid("WithDebounce and WithThrottle are mutually exclusive")
+	}
+	return o, nil
+}
+
+// invalid returns an error wrapping ErrInvalidOption.
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: "+format, append([]any{ErrInvalidOption}, args...)...)
+}
+
+// Config describes the options a queue was created with, as returned by
+// Options.
+type Config struct {
+	// Observers is the number of registered observers.
+	Observers int
+	// Metrics is the metrics provider, or nil.
+	Metrics Metrics
+	// LeaseRequeue is where nacked and expired leases are requeued.
+	LeaseRequeue RequeuePosition
+	// MaxDeliveries is the lease count after which items are
+	// dead-lettered; zero means unlimited.
+	MaxDeliveries int
+	// NodePool is the number of list nodes kept for reuse.
+	NodePool int
+	// SizeHint is the number of items the index is pre-sized for.
+	SizeHint int
+	// AutoCompact is the peak size from which the index is rebuilt
+	// automatically after draining; zero means never.
+	AutoCompact int
+	// Debounce and Throttle are the per-item push delays; zero means
+	// pushes are visible immediately.
+	Debounce time.Duration
+	Throttle time.Duration
+	// ChangeLog is the number of changes retained for ChangesSince.
+	ChangeLog int
+}
+
+// WithObserver registers obs to be notified about queue events.
+// It may be given several times to register more than one observer;
+// observers are called in registration order.
+// The type parameter must match the element type of the queue.
+func WithObserver[T comparable](obs Observer[T]) Option {
+	return func(o *options) error {
+		if obs != nil {
+			o.observers = append(o.observers, obs)
+		}
+		return nil
+	}
+}
+
+// WithMetrics reports queue depth, adds, duplicate rejections, pops and
+// time-in-queue to m.
+func WithMetrics(m Metrics) Option {
+	return func(o *options) error {
+		o.metrics = m
+		return nil
+	}
+}
+
+// WithLeaseRequeue sets where Uniqueue puts leased items that are
+// negatively acknowledged or whose lease expires. The default is
+// RequeueHead. It only applies to Uniqueue.
+func WithLeaseRequeue(pos RequeuePosition) Option {
+	return func(o *options) error {
+		if pos != RequeueHead && pos != RequeueTail {
+			return invalid("unknown lease requeue position %d", pos)
+		}
+		o.requeue = pos
+		o.uniqueueOption = "WithLeaseRequeue"
+		return nil
+	}
+}
+
+// WithMaxDeliveries moves an item to the dead-letter queue instead of
+// requeueing it once it has been leased n times without being
+// acknowledged. Zero, the default, means items are retried forever.
+// It only applies to Uniqueue; n must not be negative.
+func WithMaxDeliveries(n int) Option {
+	return func(o *options) error {
+		if n < 0 {
+			return invalid("negative max deliveries %d", n)
+		}
+		o.maxDeliveries = n
+		o.uniqueueOption = "WithMaxDeliveries"
+		return nil
+	}
+}
+
+// WithNodePool keeps up to n list nodes of removed items for reuse, making
+// steady-state pushes and pops allocation-free. See List.SetNodePool.
+// n must not be negative.
+func WithNodePool(n int) Option {
+	return func(o *options) error {
+		if n < 0 {
+			return invalid("negative node pool size %d", n)
+		}
+		o.nodePool = n
+		return nil
+	}
+}
+
+// WithSizeHint pre-sizes the queue's index for n items, so that loading a
+// large number of items does not repeatedly grow it. n is also the
+// smallest capacity Compact shrinks the index to. n must not be negative.
+func WithSizeHint(n int) Option {
+	return func(o *options) error {
+		if n < 0 {
+			return invalid("negative size hint %d", n)
+		}
+		o.sizeHint = n
+		return nil
+	}
+}
+
+// WithAutoCompact makes the queue call Compact by itself once it has
+// drained to a quarter of its peak size, provided the peak reached at
+// least threshold items. This bounds the memory a long-running queue keeps
+// after a burst, at an amortized O(1) cost per removal. threshold must be
+// positive.
+func WithAutoCompact(threshold int) Option {
+	return func(o *options) error {
+		if threshold <= 0 {
+			return invalid("non-positive auto-compact threshold %d", threshold)
+		}
+		o.autoCompact = threshold
+		return nil
+	}
+}
+
+// WithChangeLog keeps the last n changes of the queue, so that consumers
+// can follow it with ChangesSince or Uniqueue.Changes. n must be positive.
+func WithChangeLog(n int) Option {
+	return func(o *options) error {
+		if n <= 0 {
+			return invalid("non-positive change log size %d", n)
+		}
+		o.changeLog = n
+		return nil
+	}
+}
+
+// WithDebounce delays every pushed item until no push of it has been seen
+// for d: each further push restarts the wait, so a burst of pushes yields
+// a single item once the burst is over. Items are invisible to pops,
+// Contains and Size while pending. It only applies to Uniqueue; d must be
+// positive.
+func WithDebounce(d time.Duration) Option {
+	return func(o *options) error {
+		if d <= 0 {
+			return invalid("non-positive debounce interval %v", d)
+		}
+		o.debounce = d
+		o.uniqueueOption = "WithDebounce"
+		return nil
+	}
+}
+
+// WithThrottle lets each item become visible at most once per d. A push
+// within d of the item's previous release is held back until d has
+// passed, and further pushes meanwhile are ignored as duplicates. It only
+// applies to Uniqueue; d must be positive.
+func WithThrottle(d time.Duration) Option {
+	return func(o *options) error {
+		if d <= 0 {
+			return invalid("non-positive throttle interval %v", d)
+		}
+		o.throttle = d
+		o.uniqueueOption = "WithThrottle"
+		return nil
+	}
+}
+
+// withClock replaces the time source used for enqueue timestamps.
+func withClock(now func() time.Time) Option {
+	return func(o *options) error {
+		o.now = now
+		return nil
+	}
+}
+
+func observersFor[T comparable](o *options) ([]Observer[T], error) {
+	if len(o.observers) == 0 {
+		return nil, nil
+	}
+	result := make([]Observer[T], 0, len(o.observers))
+	for _, obs := range o.observers {
+		typed, ok := obs.(Observer[T])
+		if !ok {
+			var zero T
+			return nil, invalid("observer %T does not observe %T items", obs, zero)
+		}
+		result = append(result, typed)
+	}
+	return result, nil
+}
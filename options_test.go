@@ -0,0 +1,89 @@
+package uniqueue
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewUniqueueWithOptions_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"nil option", []Option{nil}},
+		{"negative max deliveries", []Option{WithMaxDeliveries(-1)}},
+		{"negative node pool", []Option{WithNodePool(-1)}},
+		{"unknown requeue position", []Option{WithLeaseRequeue(RequeuePosition(7))}},
+		{"observer of another type", []Option{WithObserver[string](ObserverFuncs[string]{})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := NewUniqueueWithOptions[int](tt.opts...)
+			if !errors.Is(err, ErrInvalidOption) {
+				t.Errorf("Expected ErrInvalidOption, got %v", err)
+			}
+			if u != nil {
+				t.Error("Expected no queue on error")
+			}
+			if _, err := NewUniqueueUnsafeWithOptions[int](tt.opts...); !errors.Is(err, ErrInvalidOption) {
+				t.Errorf("Expected ErrInvalidOption from the unsafe constructor, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewUniqueueUnsafeWithOptions_LeaseOptions(t *testing.T) {
+	for _, opt := range []Option{WithMaxDeliveries(3), WithLeaseRequeue(RequeueTail)} {
+		if _, err := NewUniqueueUnsafeWithOptions[int](opt); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("Expected ErrInvalidOption for a lease option, got %v", err)
+		}
+		if _, err := NewUniqueueWithOptions[int](opt); err != nil {
+			t.Errorf("Expected Uniqueue to accept lease options, got %v", err)
+		}
+	}
+}
+
+func TestNewUniqueue_PanicsOnInvalidOption(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for an invalid option")
+		} else if err, ok := r.(error); !ok || !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("Expected panic with ErrInvalidOption, got %v", r)
+		}
+	}()
+	NewUniqueue[int](WithNodePool(-1))
+}
+
+func TestOptions(t *testing.T) {
+	if got := NewUniqueue[int]().Options(); got != (Config{}) {
+		t.Errorf("Expected zero Config for defaults, got %+v", got)
+	}
+
+	m := NewInMemoryMetrics("jobs")
+	u, err := NewUniqueueWithOptions[int](
+		WithObserver[int](&recordingObserver{}),
+		WithObserver[int](&recordingObserver{}),
+		WithMetrics(m),
+		WithLeaseRequeue(RequeueTail),
+		WithMaxDeliveries(5),
+		WithNodePool(64),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := Config{
+		Observers:     2,
+		Metrics:       m,
+		LeaseRequeue:  RequeueTail,
+		MaxDeliveries: 5,
+		NodePool:      64,
+	}
+	if got := u.Options(); got != expected {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+
+	unsafe := NewUniqueueUnsafe[int](WithNodePool(8))
+	if got := unsafe.Options(); got != (Config{NodePool: 8}) {
+		t.Errorf("Expected NodePool 8, got %+v", got)
+	}
+}
@@ -0,0 +1,309 @@
+package uniqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+var (
+	// ErrReplication is returned when a replication peer sends a message
+	// that does not fit the protocol.
+	ErrReplication = errors.New("uniqueue: replication protocol error")
+	// ErrPromoted is returned by Follow once the follower has been
+	// promoted.
+	ErrPromoted = errors.New("uniqueue: follower has been promoted")
+	// ErrFollowing is returned by Follow while another Follow call of the
+	// same follower is running.
+	ErrFollowing = errors.New("uniqueue: follower is already following")
+)
+
+// Replication messages are JSON values, one per line.
+const (
+	msgHello    = "hello"
+	msgSnapshot = "snapshot"
+	msgChange   = "change"
+)
+
+type replMessage[T comparable] struct {
+	Type string `json:"type"`
+	// Primary identifies the queue whose sequence numbers are used. A
+	// follower sends it back in its hello to resume from Seq.
+	Primary uint64     `json:"primary,omitempty"`
+	Seq     uint64     `json:"seq,omitempty"`
+	Items   []T        `json:"items,omitempty"`
+	Change  *Change[T] `json:"change,omitempty"`
+}
+
+// closeOnDone closes conn, if it is an io.Closer, once ctx is done. This
+// unblocks pending reads and writes.
+func closeOnDone(ctx context.Context, conn io.ReadWriter) {
+	if c, ok := conn.(io.Closer); ok {
+		context.AfterFunc(ctx, func() { c.Close() })
+	}
+}
+
+// Replicate ships the queue to the follower at the other end of conn,
+// typically a net.Conn, until ctx is done or the link fails. The follower
+// resumes from the last change it applied if this queue still retains the
+// changes after it; otherwise it is sent a snapshot first. A follower that
+// falls further behind than the change log is sent a new snapshot.
+//
+// Items are encoded as JSON. Leases, dead letters and pushes held back by
+// debounce or throttle are not replicated. conn is closed on return if it
+// implements io.Closer. Replicate returns ErrNoChangeLog if the queue was
+// created without WithChangeLog; call it once per follower.
+func (u *Uniqueue[T]) Replicate(ctx context.Context, conn io.ReadWriter) error {
+	if u.Options().ChangeLog == 0 {
+		return ErrNoChangeLog
+	}
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	closeOnDone(ctx, conn)
+
+	dec := json.NewDecoder(conn)
+	var hello replMessage[T]
+	if err := dec.Decode(&hello); err != nil {
+		return err
+	}
+	if hello.Type != msgHello {
+		return fmt.Errorf("%w: expected hello, got %q", ErrReplication, hello.Type)
+	}
+
+	// The follower sends nothing after its hello, so a read only returns
+	// once the link is gone. Stop waiting for changes when it does.
+	gone := make(chan error, 1)
+	go func() {
+		var m replMessage[T]
+		err := dec.Decode(&m)
+		if err == nil {
+			err = fmt.Errorf("%w: unexpected %q from follower", ErrReplication, m.Type)
+		}
+		gone <- err
+		cancel()
+	}()
+
+	enc := json.NewEncoder(conn)
+	since := hello.Seq
+	resume := hello.Primary == u.id && since <= u.Seq()
+	for {
+		if !resume {
+			var items []T
+			since, items = u.snapshot()
+			err := enc.Encode(replMessage[T]{Type: msgSnapshot, Primary: u.id, Seq: since, Items: items})
+			if err != nil {
+				return replicateErr(parent, gone, err)
+			}
+		}
+		resume = false
+
+		for c, err := range u.Changes(ctx, since) {
+			if errors.Is(err, ErrChangeLogTruncated) {
+				break
+			}
+			if err != nil {
+				return replicateErr(parent, gone, err)
+			}
+			if err := enc.Encode(replMessage[T]{Type: msgChange, Change: &c}); err != nil {
+				return replicateErr(parent, gone, err)
+			}
+		}
+	}
+}
+
+// replicateErr reports why Replicate stopped: the caller's ctx being done,
+// or else the follower going away, over the error that caused.
+func replicateErr(ctx context.Context, gone chan error, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	select {
+	case goneErr := <-gone:
+		return goneErr
+	default:
+		return err
+	}
+}
+
+// snapshot returns the sequence number and the items of the queue.
+func (u *Uniqueue[T]) snapshot() (uint64, []T) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.uniqueue.Seq(), u.uniqueue.ToSlice()
+}
+
+// load replaces the items of the queue.
+func (u *Uniqueue[T]) load(items []T) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.uniqueue.Clear()
+	for _, item := range items {
+		u.uniqueue.PushBack(item)
+	}
+	u.forgetAttempts()
+	u.broadcast()
+}
+
+// FollowerStatus describes the replication state of a Follower.
+type FollowerStatus struct {
+	// Applied is the primary's sequence number the follower has caught
+	// up to.
+	Applied uint64
+	// Snapshots and Changes count the snapshots loaded and the changes
+	// applied.
+	Snapshots int
+	Changes   int
+	Promoted  bool
+}
+
+// Follower keeps a Uniqueue in sync with a primary that runs Replicate.
+// Follow may be called again with a new connection after the link fails;
+// the follower then catches up from where it stopped. All methods are safe
+// for concurrent use.
+type Follower[T comparable] struct {
+	queue *Uniqueue[T]
+
+	mu        sync.Mutex
+	primary   uint64
+	applied   uint64
+	snapshots int
+	changes   int
+	promoted  bool
+	// cancel stops the running Follow call, if any.
+	cancel context.CancelFunc
+}
+
+// NewFollower returns a follower that replicates into queue. queue should
+// not be modified by anything else until the follower is promoted.
+func NewFollower[T comparable](queue *Uniqueue[T]) *Follower[T] {
+	return &Follower[T]{queue: queue}
+}
+
+// Queue returns the replicated queue.
+func (f *Follower[T]) Queue() *Uniqueue[T] {
+	return f.queue
+}
+
+// Status returns the replication state of the follower.
+func (f *Follower[T]) Status() FollowerStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return FollowerStatus{
+		Applied:   f.applied,
+		Snapshots: f.snapshots,
+		Changes:   f.changes,
+		Promoted:  f.promoted,
+	}
+}
+
+// Follow applies what the primary at the other end of conn sends until
+// ctx is done, the link fails or the follower is promoted, and returns the
+// reason: ctx.Err(), the read error, ErrReplication or ErrPromoted. conn is
+// closed on return if it implements io.Closer.
+func (f *Follower[T]) Follow(ctx context.Context, conn io.ReadWriter) error {
+	f.mu.Lock()
+	if f.promoted {
+		f.mu.Unlock()
+		return ErrPromoted
+	}
+	if f.cancel != nil {
+		f.mu.Unlock()
+		return ErrFollowing
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	f.cancel = cancel
+	hello := replMessage[T]{Type: msgHello, Primary: f.primary, Seq: f.applied}
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.cancel = nil
+		f.mu.Unlock()
+		cancel()
+	}()
+	closeOnDone(ctx, conn)
+
+	if err := json.NewEncoder(conn).Encode(hello); err != nil {
+		return f.followErr(ctx, err)
+	}
+	dec := json.NewDecoder(conn)
+	for {
+		var m replMessage[T]
+		if err := dec.Decode(&m); err != nil {
+			return f.followErr(ctx, err)
+		}
+		if err := f.apply(m); err != nil {
+			return err
+		}
+	}
+}
+
+func (f *Follower[T]) followErr(ctx context.Context, err error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.promoted {
+		return ErrPromoted
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// apply handles one message from the primary. Changes the follower has
+// already applied are skipped.
+func (f *Follower[T]) apply(m replMessage[T]) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.promoted {
+		return ErrPromoted
+	}
+	switch m.Type {
+	case msgSnapshot:
+		f.queue.load(m.Items)
+		f.primary = m.Primary
+		f.applied = m.Seq
+		f.snapshots++
+	case msgChange:
+		c := m.Change
+		if c == nil {
+			return fmt.Errorf("%w: change message without a change", ErrReplication)
+		}
+		if c.Seq <= f.applied {
+			return nil
+		}
+		if c.Seq != f.applied+1 {
+			return fmt.Errorf("%w: expected change %d, got %d", ErrReplication, f.applied+1, c.Seq)
+		}
+		f.queue.Apply(*c)
+		f.applied = c.Seq
+		f.changes++
+	default:
+		return fmt.Errorf("%w: unexpected %q from primary", ErrReplication, m.Type)
+	}
+	return nil
+}
+
+// Promote stops following and returns the queue, which from now on may be
+// written to and replicated to followers of its own. No change from the
+// old primary is applied after Promote returns; a running Follow call
+// returns ErrPromoted.
+func (f *Follower[T]) Promote() *Uniqueue[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.promoted = true
+	if f.cancel != nil {
+		f.cancel()
+	}
+	return f.queue
+}
@@ -0,0 +1,216 @@
+package uniqueue
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+)
+
+// link connects primary and f over a pipe. Calling cut closes the link and
+// waits for both ends to return.
+func link[T comparable](t *testing.T, primary *Uniqueue[T], f *Follower[T]) (cut func() (primaryErr, followErr error)) {
+	t.Helper()
+	a, b := net.Pipe()
+	ctx, cancel := context.WithCancel(t.Context())
+	primaryDone := make(chan error, 1)
+	followDone := make(chan error, 1)
+	go func() { primaryDone <- primary.Replicate(ctx, a) }()
+	go func() { followDone <- f.Follow(ctx, b) }()
+	t.Cleanup(cancel)
+	return func() (error, error) {
+		a.Close()
+		return <-primaryDone, <-followDone
+	}
+}
+
+func inSync[T comparable](primary *Uniqueue[T], f *Follower[T]) func() bool {
+	return func() bool {
+		return f.Status().Applied == primary.Seq() &&
+			slices.Equal(primary.PeekN(primary.Size()), f.Queue().PeekN(f.Queue().Size()))
+	}
+}
+
+func TestReplicate(t *testing.T) {
+	primary := NewUniqueue[string](WithChangeLog(100))
+	primary.PushBack("a")
+	primary.PushBack("b")
+
+	f := NewFollower(NewUniqueue[string]())
+	cut := link(t, primary, f)
+	eventually(t, inSync(primary, f), "Expected the follower to load the snapshot")
+
+	primary.PushBack("c")
+	primary.PushFront("z")
+	primary.MoveAfter("z", "b")
+	primary.Replace("a", "x")
+	primary.PopHead()
+	primary.Remove("c")
+	primary.PushBack("d")
+	eventually(t, inSync(primary, f), "Expected the follower to apply the changes")
+
+	status := f.Status()
+	if status.Snapshots != 1 || status.Changes != 7 {
+		t.Errorf("Expected 1 snapshot and 7 changes, got %+v", status)
+	}
+
+	_, followErr := cut()
+	if followErr == nil {
+		t.Error("Expected Follow to fail when the link is cut")
+	}
+}
+
+func TestReplicate_CatchUp(t *testing.T) {
+	primary := NewUniqueue[int](WithChangeLog(10))
+	f := NewFollower(NewUniqueue[int]())
+
+	cut := link(t, primary, f)
+	primary.PushBack(1)
+	primary.PushBack(2)
+	eventually(t, inSync(primary, f), "Expected the follower to catch up")
+	cut()
+
+	// Missed changes still in the change log are resent.
+	primary.PushBack(3)
+	primary.PopHead()
+	if f.Queue().Contains(3) {
+		t.Fatal("Expected no changes while disconnected")
+	}
+	cut = link(t, primary, f)
+	eventually(t, inSync(primary, f), "Expected the follower to catch up after reconnecting")
+	if s := f.Status(); s.Snapshots != 1 {
+		t.Errorf("Expected catch-up without a new snapshot, got %d snapshots", s.Snapshots)
+	}
+	cut()
+
+	// Falling behind the change log takes a new snapshot.
+	for i := range 20 {
+		primary.PushBack(100 + i)
+	}
+	link(t, primary, f)
+	eventually(t, inSync(primary, f), "Expected the follower to resync")
+	if s := f.Status(); s.Snapshots != 2 {
+		t.Errorf("Expected a second snapshot, got %d snapshots", s.Snapshots)
+	}
+}
+
+func TestReplicate_NewPrimary(t *testing.T) {
+	first := NewUniqueue[int](WithChangeLog(10))
+	first.PushBack(1)
+	f := NewFollower(NewUniqueue[int]())
+	cut := link(t, first, f)
+	eventually(t, inSync(first, f), "Expected the follower to catch up")
+	cut()
+
+	// Sequence numbers of another primary are not comparable, so a
+	// snapshot is sent even though its seq is the same.
+	second := NewUniqueue[int](WithChangeLog(10))
+	second.PushBack(2)
+	link(t, second, f)
+	eventually(t, inSync(second, f), "Expected the follower to switch primaries")
+	if f.Queue().Contains(1) {
+		t.Error("Expected items of the old primary to be dropped")
+	}
+}
+
+func TestReplicate_MultipleFollowers(t *testing.T) {
+	primary := NewUniqueue[int](WithChangeLog(100))
+	followers := make([]*Follower[int], 3)
+	for i := range followers {
+		followers[i] = NewFollower(NewUniqueue[int]())
+		link(t, primary, followers[i])
+	}
+	for i := range 20 {
+		primary.PushBack(i)
+		if i%3 == 0 {
+			primary.PopHead()
+		}
+	}
+	for _, f := range followers {
+		eventually(t, inSync(primary, f), "Expected every follower to catch up")
+	}
+}
+
+func TestFollower_Promote(t *testing.T) {
+	primary := NewUniqueue[string](WithChangeLog(10))
+	primary.PushBack("a")
+	f := NewFollower(NewUniqueue[string](WithChangeLog(10)))
+	cut := link(t, primary, f)
+	eventually(t, inSync(primary, f), "Expected the follower to catch up")
+
+	q := f.Promote()
+	primary.PushBack("b")
+	q.PushBack("c")
+	_, followErr := cut()
+	if !errors.Is(followErr, ErrPromoted) {
+		t.Errorf("Expected ErrPromoted, got %v", followErr)
+	}
+	if got := q.PeekN(10); !slices.Equal(got, []string{"a", "c"}) {
+		t.Errorf("Expected [a c] after promotion, got %v", got)
+	}
+	if err := f.Follow(t.Context(), nil); !errors.Is(err, ErrPromoted) {
+		t.Errorf("Expected ErrPromoted from Follow after promotion, got %v", err)
+	}
+	if !f.Status().Promoted {
+		t.Error("Expected status to report the promotion")
+	}
+
+	// The promoted queue serves followers of its own.
+	g := NewFollower(NewUniqueue[string]())
+	link(t, q, g)
+	eventually(t, inSync(q, g), "Expected a follower of the promoted queue to catch up")
+}
+
+func TestFollower_Apply(t *testing.T) {
+	f := NewFollower(NewUniqueue[int]())
+	if err := f.apply(replMessage[int]{Type: msgSnapshot, Primary: 1, Seq: 5, Items: []int{1, 2}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	push := replMessage[int]{Type: msgChange, Change: &Change[int]{Seq: 6, Kind: ChangePushBack, Item: 3}}
+	for range 2 {
+		if err := f.apply(push); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	}
+	old := replMessage[int]{Type: msgChange, Change: &Change[int]{Seq: 4, Kind: ChangeClear}}
+	if err := f.apply(old); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if got := f.Queue().PeekN(10); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Expected duplicate and old changes to be skipped, got %v", got)
+	}
+
+	gap := replMessage[int]{Type: msgChange, Change: &Change[int]{Seq: 8, Kind: ChangeClear}}
+	if err := f.apply(gap); !errors.Is(err, ErrReplication) {
+		t.Errorf("Expected ErrReplication for a gap, got %v", err)
+	}
+	if err := f.apply(replMessage[int]{Type: "bogus"}); !errors.Is(err, ErrReplication) {
+		t.Errorf("Expected ErrReplication for an unknown message, got %v", err)
+	}
+}
+
+func TestReplicate_NoChangeLog(t *testing.T) {
+	a, b := net.Pipe()
+	defer b.Close()
+	if err := NewUniqueue[int]().Replicate(t.Context(), a); !errors.Is(err, ErrNoChangeLog) {
+		t.Errorf("Expected ErrNoChangeLog, got %v", err)
+	}
+}
+
+func TestReplicate_ContextDone(t *testing.T) {
+	primary := NewUniqueue[int](WithChangeLog(10))
+	f := NewFollower(NewUniqueue[int]())
+	a, b := net.Pipe()
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- primary.Replicate(ctx, a) }()
+	go f.Follow(t.Context(), b)
+
+	eventually(t, func() bool { return f.Status().Snapshots == 1 }, "Expected a snapshot")
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
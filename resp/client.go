@@ -0,0 +1,60 @@
+package resp
+
+import (
+	"bufio"
+	"net"
+	"sync"
+)
+
+// Client is a minimal RESP client, enough to talk to a Server or to Redis.
+// It is safe for concurrent use; commands are sent one at a time.
+type Client struct {
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+	w    *writer
+}
+
+// Dial connects to the RESP server at the TCP address addr.
+func Dial(addr string) (*Client, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// NewClient creates a client talking over conn.
+func NewClient(conn net.Conn) *Client {
+	return &Client{
+		conn: conn,
+		r:    bufio.NewReader(conn),
+		w:    newWriter(conn),
+	}
+}
+
+// Do sends a command and returns its reply: a string for simple and bulk
+// strings, int64 for integers, []any for arrays and nil for null replies.
+// An error reply is returned as an Error.
+func (c *Client) Do(args ...string) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.w.bulks(args)
+	if err := c.w.flush(); err != nil {
+		return nil, err
+	}
+	v, err := readValue(c.r)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := v.(Error); ok {
+		return nil, e
+	}
+	return v, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
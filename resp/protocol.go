@@ -0,0 +1,244 @@
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Limits protecting the server from oversized or malicious input.
+const (
+	maxBulkSize  = 8 << 20
+	maxArraySize = 1 << 20
+	maxLineSize  = 64 << 10
+	// maxDepth is how deeply replies may nest arrays.
+	maxDepth = 8
+)
+
+// Error is an error reply sent by the server, such as "ERR unknown command".
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// errProtocol is returned when the peer sends malformed RESP.
+var errProtocol = errors.New("resp: protocol error")
+
+// readLine reads a CRLF terminated line without the terminator.
+func readLine(r *bufio.Reader) (string, error) {
+	var sb strings.Builder
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			return "", err
+		}
+		sb.Write(chunk)
+		if sb.Len() > maxLineSize {
+			return "", fmt.Errorf("%w: line too long", errProtocol)
+		}
+		if !isPrefix {
+			return sb.String(), nil
+		}
+	}
+}
+
+// readValue reads one RESP value. Simple and bulk strings are returned as
+// string, integers as int64, arrays as []any and null bulk strings and
+// arrays as nil. Error replies are returned as an Error value, not as err.
+func readValue(r *bufio.Reader) (any, error) {
+	return readNested(r, 0)
+}
+
+// readNested reads a value nested in depth arrays.
+func readNested(r *bufio.Reader, depth int) (any, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, fmt.Errorf("%w: empty line", errProtocol)
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return Error(line[1:]), nil
+	case ':':
+		n, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid integer", errProtocol)
+		}
+		return n, nil
+	case '$':
+		s, ok, err := readBulk(r, line)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return s, nil
+	case '*':
+		n, err := parseArrayLength(line)
+		if err != nil {
+			return nil, err
+		}
+		if n == -1 {
+			return nil, nil
+		}
+		if depth >= maxDepth {
+			return nil, fmt.Errorf("%w: arrays nested too deeply", errProtocol)
+		}
+		arr := make([]any, n)
+		for i := range arr {
+			if arr[i], err = readNested(r, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	}
+	return nil, fmt.Errorf("%w: unexpected type byte %q", errProtocol, line[0])
+}
+
+func parseArrayLength(line string) (int, error) {
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < -1 || n > maxArraySize {
+		return 0, fmt.Errorf("%w: invalid array length", errProtocol)
+	}
+	return n, nil
+}
+
+// readBulk reads the body of the bulk string whose header line has been
+// read. ok is false for a null bulk string.
+func readBulk(r *bufio.Reader, line string) (s string, ok bool, err error) {
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < -1 || n > maxBulkSize {
+		return "", false, fmt.Errorf("%w: invalid bulk length", errProtocol)
+	}
+	if n == -1 {
+		return "", false, nil
+	}
+	buf := make([]byte, n+2)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", false, err
+	}
+	if buf[n] != '\r' || buf[n+1] != '\n' {
+		return "", false, fmt.Errorf("%w: missing CRLF after bulk string", errProtocol)
+	}
+	return string(buf[:n]), true, nil
+}
+
+// readCommand reads a command sent as a RESP array of bulk strings or as
+// an inline, space separated line as typed into telnet. Anything but a bulk
+// string inside the array is rejected as soon as it is seen.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	b, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	if b[0] != '*' {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		return strings.Fields(line), nil
+	}
+
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	n, err := parseArrayLength(line)
+	if err != nil || n == -1 {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if line == "" || line[0] != '$' {
+			return nil, fmt.Errorf("%w: command arguments must be bulk strings", errProtocol)
+		}
+		s, ok, err := readBulk(r, line)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: command arguments must not be null", errProtocol)
+		}
+		args[i] = s
+	}
+	return args, nil
+}
+
+// writer encodes RESP replies and commands. Errors are sticky and reported
+// by flush.
+type writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func newWriter(w io.Writer) *writer {
+	return &writer{w: bufio.NewWriter(w)}
+}
+
+func (w *writer) printf(format string, args ...any) {
+	if w.err == nil {
+		_, w.err = fmt.Fprintf(w.w, format, args...)
+	}
+}
+
+func (w *writer) simple(s string) {
+	w.printf("+%s\r\n", s)
+}
+
+func (w *writer) error(msg string) {
+	w.printf("-%s\r\n", strings.NewReplacer("\r", " ", "\n", " ").Replace(msg))
+}
+
+func (w *writer) integer(n int) {
+	w.printf(":%d\r\n", n)
+}
+
+func (w *writer) bulk(s string) {
+	w.printf("$%d\r\n%s\r\n", len(s), s)
+}
+
+// optional writes s as a bulk string if ok, or a null reply otherwise.
+func (w *writer) optional(s string, ok bool) {
+	if !ok {
+		w.null()
+		return
+	}
+	w.bulk(s)
+}
+
+func (w *writer) null() {
+	w.printf("$-1\r\n")
+}
+
+func (w *writer) nullArray() {
+	w.printf("*-1\r\n")
+}
+
+func (w *writer) arrayHeader(n int) {
+	w.printf("*%d\r\n", n)
+}
+
+func (w *writer) bulks(items []string) {
+	w.arrayHeader(len(items))
+	for _, item := range items {
+		w.bulk(item)
+	}
+}
+
+func (w *writer) flush() error {
+	if w.err == nil {
+		w.err = w.w.Flush()
+	}
+	return w.err
+}
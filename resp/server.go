@@ -0,0 +1,535 @@
+// Package resp exposes named uniqueue.Uniqueue instances over TCP using a
+// subset of the Redis serialization protocol (RESP), so that tooling which
+// already speaks Redis lists can use a deduplicating queue.
+//
+// Every key names a queue of strings, created by the first push; other
+// commands treat a missing key as an empty list, and blocking pops wait
+// for it to be created. The left end of
+// a list is the head of the queue and the right end its tail; pushing an
+// item that is already queued or leased is a no-op. Supported commands:
+//
+//	PING [message]
+//	ECHO message
+//	LPUSH key item [item ...]    push to the head, returns the length
+//	RPUSH key item [item ...]    push to the tail, returns the length
+//	LPOP key / RPOP key          pop from the head / tail, nil when empty
+//	BLPOP key timeout            blocking LPOP, timeout in seconds, 0 waits forever
+//	BRPOP key timeout            blocking RPOP
+//	LLEN key
+//	LRANGE key start stop
+//	LPOS key item                position from the head, nil if absent
+//	LREM key count item          remove item, returns 0 or 1
+//	SISMEMBER key item           1 if item is queued, else 0
+//	DEL key [key ...]            clear queues, returns how many were non-empty
+//	QUIT
+//
+// Unlike Redis, BLPOP and BRPOP accept a single key. A blocked pop ends
+// when the timeout elapses, the client disconnects or the server is closed.
+// An item popped for a client that cannot be sent the reply is put back.
+package resp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"math"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/realfatcat/uniqueue"
+)
+
+// ErrServerClosed is returned by Serve after Close has been called.
+var ErrServerClosed = errors.New("resp: server closed")
+
+// Server serves a set of named queues of strings over RESP.
+type Server struct {
+	opts []uniqueue.Option
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	queues map[string]*uniqueue.Uniqueue[string]
+	// added is closed and replaced whenever a queue is added, waking
+	// blocking pops that wait for their queue to be created.
+	added     chan struct{}
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// New creates a server. opts are applied to every queue it creates.
+// It panics if the options are invalid; use NewWithOptions to get an
+// error instead.
+func New(opts ...uniqueue.Option) *Server {
+	s, err := NewWithOptions(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// NewWithOptions is like New but returns an error wrapping
+// uniqueue.ErrInvalidOption if the options are invalid, so that they are
+// rejected up front rather than when the first queue is created.
+func NewWithOptions(opts ...uniqueue.Option) (*Server, error) {
+	if _, err := uniqueue.NewUniqueueWithOptions[string](opts...); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Server{
+		opts:      opts,
+		ctx:       ctx,
+		cancel:    cancel,
+		queues:    make(map[string]*uniqueue.Uniqueue[string]),
+		added:     make(chan struct{}),
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// Queue returns the queue registered under name, creating it if needed.
+func (s *Server) Queue(name string) *uniqueue.Uniqueue[string] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.queues[name]
+	if !ok {
+		q = uniqueue.NewUniqueue[string](s.opts...)
+		s.add(name, q)
+	}
+	return q
+}
+
+// Register serves q under name, replacing any queue with the same name.
+func (s *Server) Register(name string, q *uniqueue.Uniqueue[string]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.add(name, q)
+}
+
+// add stores q under name and wakes waiting pops. Must be called with the
+// lock held.
+func (s *Server) add(name string, q *uniqueue.Uniqueue[string]) {
+	s.queues[name] = q
+	close(s.added)
+	s.added = make(chan struct{})
+}
+
+// Names returns the names of all queues in sorted order.
+func (s *Server) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.queues))
+	for name := range s.queues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookup returns the queue registered under name without creating it.
+func (s *Server) lookup(name string) (*uniqueue.Uniqueue[string], bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.queues[name]
+	return q, ok
+}
+
+// await returns the queue registered under name, waiting for it to be
+// created until ctx is done.
+func (s *Server) await(ctx context.Context, name string) (*uniqueue.Uniqueue[string], error) {
+	for {
+		s.mu.Lock()
+		q, ok := s.queues[name]
+		added := s.added
+		s.mu.Unlock()
+		if ok {
+			return q, nil
+		}
+		select {
+		case <-added:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// ListenAndServe listens on the TCP address addr and calls Serve.
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on l and serves each in its own goroutine
+// until Close is called, after which it returns ErrServerClosed. l is
+// closed on return.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		l.Close()
+		return ErrServerClosed
+	}
+	s.listeners[l] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.listeners, l)
+		s.mu.Unlock()
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return ErrServerClosed
+			}
+			return err
+		}
+		if !s.track(conn) {
+			conn.Close()
+			return ErrServerClosed
+		}
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+// Close stops all listeners, wakes blocked pops, closes every connection
+// and waits for their goroutines to finish. Queues are left intact.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	s.cancel()
+	for l := range s.listeners {
+		l.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+
+	c := &session{conn: conn, r: bufio.NewReader(conn), writer: newWriter(conn)}
+	for {
+		args, err := readCommand(c.r)
+		if err != nil {
+			if errors.Is(err, errProtocol) {
+				c.error("ERR " + err.Error())
+				c.flush()
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		quit := s.exec(c, args)
+		// Flush only once pipelined commands have all been answered.
+		if quit || c.r.Buffered() == 0 {
+			if c.flush() != nil || quit {
+				return
+			}
+		}
+	}
+}
+
+// session is the connection of one client. Replies are written through
+// the embedded writer.
+type session struct {
+	conn net.Conn
+	r    *bufio.Reader
+	*writer
+}
+
+// watch returns a context that is cancelled when the client disconnects,
+// for commands that block without reading. stop ends the watch and must be
+// called before c.r is used again. Pipelined input is left in c.r; once
+// some has arrived, a disconnect is no longer noticed.
+func (c *session) watch(parent context.Context) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(parent)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := c.r.Peek(1); err != nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+			cancel()
+		}
+	}()
+	return ctx, func() {
+		// Interrupt the pending Peek, then allow reads again.
+		c.conn.SetReadDeadline(time.Now())
+		<-done
+		c.conn.SetReadDeadline(time.Time{})
+		cancel()
+	}
+}
+
+// command describes how to run a command and how many arguments it takes,
+// including the command name. A negative arity means at least -arity.
+type command struct {
+	arity int
+	run   func(s *Server, c *session, args []string)
+}
+
+var commands = map[string]command{
+	"PING":      {-1, (*Server).ping},
+	"ECHO":      {2, func(s *Server, c *session, args []string) { c.bulk(args[1]) }},
+	"LPUSH":     {-3, (*Server).lpush},
+	"RPUSH":     {-3, (*Server).rpush},
+	"LPOP":      {2, (*Server).lpop},
+	"RPOP":      {2, (*Server).rpop},
+	"BLPOP":     {3, (*Server).blpop},
+	"BRPOP":     {3, (*Server).brpop},
+	"LLEN":      {2, (*Server).llen},
+	"LRANGE":    {4, (*Server).lrange},
+	"LPOS":      {3, (*Server).lpos},
+	"LREM":      {4, (*Server).lrem},
+	"SISMEMBER": {3, (*Server).sismember},
+	"DEL":       {-2, (*Server).del},
+	"COMMAND":   {-1, func(s *Server, c *session, args []string) { c.arrayHeader(0) }},
+}
+
+// exec runs one command and reports whether the connection should close.
+func (s *Server) exec(c *session, args []string) bool {
+	name := strings.ToUpper(args[0])
+	if name == "QUIT" {
+		c.simple("OK")
+		return true
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		c.error("ERR unknown command '" + args[0] + "'")
+		return false
+	}
+	if (cmd.arity >= 0 && len(args) != cmd.arity) || (cmd.arity < 0 && len(args) < -cmd.arity) {
+		c.error("ERR wrong number of arguments for '" + strings.ToLower(name) + "' command")
+		return false
+	}
+	cmd.run(s, c, args)
+	return false
+}
+
+func (s *Server) ping(c *session, args []string) {
+	switch len(args) {
+	case 1:
+		c.simple("PONG")
+	case 2:
+		c.bulk(args[1])
+	default:
+		c.error("ERR wrong number of arguments for 'ping' command")
+	}
+}
+
+func (s *Server) lpush(c *session, args []string) {
+	q := s.Queue(args[1])
+	for _, item := range args[2:] {
+		q.PushFront(item)
+	}
+	c.integer(q.Size())
+}
+
+func (s *Server) rpush(c *session, args []string) {
+	q := s.Queue(args[1])
+	for _, item := range args[2:] {
+		q.PushBack(item)
+	}
+	c.integer(q.Size())
+}
+
+func (s *Server) lpop(c *session, args []string) {
+	q, ok := s.lookup(args[1])
+	if !ok {
+		c.null()
+		return
+	}
+	c.optional(q.PopHead())
+}
+
+func (s *Server) rpop(c *session, args []string) {
+	q, ok := s.lookup(args[1])
+	if !ok {
+		c.null()
+		return
+	}
+	c.optional(q.PopTail())
+}
+
+func (s *Server) blpop(c *session, args []string) {
+	s.blockingPop(c, args, (*uniqueue.Uniqueue[string]).PopHeadWait, (*uniqueue.Uniqueue[string]).PushFront)
+}
+
+func (s *Server) brpop(c *session, args []string) {
+	s.blockingPop(c, args, (*uniqueue.Uniqueue[string]).PopTailWait, (*uniqueue.Uniqueue[string]).PushBack)
+}
+
+// blockingPop waits for pop to return an item and sends it to the client.
+// If the reply cannot be sent, the item is returned to the queue with
+// putBack.
+func (s *Server) blockingPop(c *session, args []string,
+	pop func(*uniqueue.Uniqueue[string], context.Context) (string, error),
+	putBack func(*uniqueue.Uniqueue[string], string),
+) {
+	secs, err := strconv.ParseFloat(args[2], 64)
+	if err != nil || secs < 0 || math.IsInf(secs, 0) || math.IsNaN(secs) {
+		c.error("ERR timeout is not a float or out of range")
+		return
+	}
+
+	// Send the replies to any pipelined commands before blocking.
+	if c.flush() != nil {
+		return
+	}
+	ctx, stop := c.watch(s.ctx)
+	defer stop()
+	if secs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(secs*float64(time.Second)))
+		defer cancel()
+	}
+	q, err := s.await(ctx, args[1])
+	if err != nil {
+		c.nullArray()
+		return
+	}
+	item, err := pop(q, ctx)
+	if err != nil {
+		c.nullArray()
+		return
+	}
+	c.bulks([]string{args[1], item})
+	if c.flush() != nil {
+		putBack(q, item)
+	}
+}
+
+func (s *Server) llen(c *session, args []string) {
+	q, ok := s.lookup(args[1])
+	if !ok {
+		c.integer(0)
+		return
+	}
+	c.integer(q.Size())
+}
+
+func (s *Server) lrange(c *session, args []string) {
+	start, err1 := strconv.Atoi(args[2])
+	stop, err2 := strconv.Atoi(args[3])
+	if err1 != nil || err2 != nil {
+		c.error("ERR value is not an integer or out of range")
+		return
+	}
+	q, ok := s.lookup(args[1])
+	if !ok {
+		c.arrayHeader(0)
+		return
+	}
+
+	var items []string
+	if start >= 0 && stop >= 0 {
+		items = q.PeekN(min(stop, math.MaxInt-1) + 1)
+	} else {
+		items = q.PeekN(math.MaxInt)
+	}
+	n := len(items)
+	if start < 0 {
+		start = max(n+start, 0)
+	}
+	if stop < 0 {
+		stop = n + stop
+	}
+	stop = min(stop, n-1)
+	if start > stop {
+		c.arrayHeader(0)
+		return
+	}
+	c.bulks(items[start : stop+1])
+}
+
+func (s *Server) lpos(c *session, args []string) {
+	q, ok := s.lookup(args[1])
+	if !ok {
+		c.null()
+		return
+	}
+	i, ok := q.IndexOf(args[2])
+	if !ok {
+		c.null()
+		return
+	}
+	c.integer(i)
+}
+
+// lrem accepts Redis' count argument for compatibility; since an item is
+// queued at most once, any count removes it.
+func (s *Server) lrem(c *session, args []string) {
+	if _, err := strconv.Atoi(args[2]); err != nil {
+		c.error("ERR value is not an integer or out of range")
+		return
+	}
+	q, ok := s.lookup(args[1])
+	if ok && q.Remove(args[3]) {
+		c.integer(1)
+		return
+	}
+	c.integer(0)
+}
+
+func (s *Server) sismember(c *session, args []string) {
+	q, ok := s.lookup(args[1])
+	if ok && q.Contains(args[2]) {
+		c.integer(1)
+		return
+	}
+	c.integer(0)
+}
+
+func (s *Server) del(c *session, args []string) {
+	n := 0
+	for _, name := range args[1:] {
+		if q, ok := s.lookup(name); ok && !q.IsEmpty() {
+			q.Clear()
+			n++
+		}
+	}
+	c.integer(n)
+}
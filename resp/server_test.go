@@ -0,0 +1,294 @@
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/realfatcat/uniqueue"
+)
+
+func newTestServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	s := New()
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(l) }()
+	t.Cleanup(func() {
+		s.Close()
+		if err := <-done; err != ErrServerClosed {
+			t.Errorf("Expected ErrServerClosed, got %v", err)
+		}
+	})
+	return s, l.Addr().String()
+}
+
+func dial(t *testing.T, addr string) *Client {
+	t.Helper()
+	c, err := Dial(addr)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// do runs a command that is expected to succeed and returns its reply.
+func do(t *testing.T, c *Client, args ...string) any {
+	t.Helper()
+	v, err := c.Do(args...)
+	if err != nil {
+		t.Fatalf("%v: expected no error, got %v", args, err)
+	}
+	return v
+}
+
+func assertReply(t *testing.T, c *Client, expected any, args ...string) {
+	t.Helper()
+	if got := do(t, c, args...); !reflect.DeepEqual(got, expected) {
+		t.Errorf("%v: expected %#v, got %#v", args, expected, got)
+	}
+}
+
+func TestServer_Commands(t *testing.T) {
+	_, addr := newTestServer(t)
+	c := dial(t, addr)
+
+	assertReply(t, c, "PONG", "PING")
+	assertReply(t, c, "hi", "ECHO", "hi")
+
+	assertReply(t, c, int64(2), "RPUSH", "jobs", "b", "c")
+	assertReply(t, c, int64(3), "LPUSH", "jobs", "a", "c", "b")
+	assertReply(t, c, []any{"a", "b", "c"}, "LRANGE", "jobs", "0", "-1")
+	assertReply(t, c, []any{"b", "c"}, "LRANGE", "jobs", "-2", "10")
+	assertReply(t, c, []any{}, "LRANGE", "jobs", "2", "1")
+	assertReply(t, c, []any{"a", "b", "c"}, "LRANGE", "jobs", "0", "9223372036854775807")
+	assertReply(t, c, int64(3), "LLEN", "jobs")
+	assertReply(t, c, int64(1), "LPOS", "jobs", "b")
+	assertReply(t, c, nil, "LPOS", "jobs", "z")
+	assertReply(t, c, int64(1), "SISMEMBER", "jobs", "c")
+	assertReply(t, c, int64(0), "SISMEMBER", "jobs", "z")
+
+	assertReply(t, c, "a", "LPOP", "jobs")
+	assertReply(t, c, "c", "RPOP", "jobs")
+	assertReply(t, c, int64(1), "LREM", "jobs", "0", "b")
+	assertReply(t, c, int64(0), "LREM", "jobs", "0", "b")
+	assertReply(t, c, nil, "LPOP", "jobs")
+
+	assertReply(t, c, int64(0), "LLEN", "missing")
+	assertReply(t, c, []any{}, "LRANGE", "missing", "0", "-1")
+
+	do(t, c, "RPUSH", "a", "x")
+	do(t, c, "RPUSH", "b", "x")
+	assertReply(t, c, int64(2), "DEL", "a", "b", "jobs", "missing")
+	assertReply(t, c, int64(0), "LLEN", "a")
+}
+
+func TestServer_Errors(t *testing.T) {
+	_, addr := newTestServer(t)
+	c := dial(t, addr)
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"NOPE"}, "ERR unknown command 'NOPE'"},
+		{[]string{"LPOP"}, "ERR wrong number of arguments for 'lpop' command"},
+		{[]string{"RPUSH", "jobs"}, "ERR wrong number of arguments for 'rpush' command"},
+		{[]string{"BLPOP", "a", "b", "1"}, "ERR wrong number of arguments for 'blpop' command"},
+		{[]string{"BLPOP", "jobs", "soon"}, "ERR timeout is not a float or out of range"},
+		{[]string{"LRANGE", "jobs", "a", "1"}, "ERR value is not an integer or out of range"},
+	}
+	for _, tt := range tests {
+		_, err := c.Do(tt.args...)
+		var e Error
+		if !errors.As(err, &e) || string(e) != tt.expected {
+			t.Errorf("%v: expected %q, got %v", tt.args, tt.expected, err)
+		}
+	}
+
+	// The connection is still usable after error replies.
+	assertReply(t, c, "PONG", "PING")
+}
+
+func TestServer_ReadsDoNotCreateQueues(t *testing.T) {
+	s, addr := newTestServer(t)
+	c := dial(t, addr)
+
+	assertReply(t, c, nil, "LPOP", "missing")
+	assertReply(t, c, nil, "RPOP", "missing")
+	assertReply(t, c, nil, "BLPOP", "missing", "0.01")
+	assertReply(t, c, nil, "BRPOP", "missing", "0.01")
+	assertReply(t, c, int64(0), "LLEN", "missing")
+	if names := s.Names(); len(names) != 0 {
+		t.Errorf("Expected reads not to create queues, got %v", names)
+	}
+}
+
+func TestServer_BlockingPop(t *testing.T) {
+	s, addr := newTestServer(t)
+	consumer := dial(t, addr)
+	producer := dial(t, addr)
+
+	done := make(chan any, 1)
+	go func() {
+		v, _ := consumer.Do("BLPOP", "jobs", "0")
+		done <- v
+	}()
+
+	select {
+	case v := <-done:
+		t.Fatalf("Expected BLPOP to block on an empty queue, got %v", v)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	do(t, producer, "RPUSH", "jobs", "x")
+	select {
+	case v := <-done:
+		if !reflect.DeepEqual(v, []any{"jobs", "x"}) {
+			t.Errorf("Expected [jobs x], got %v", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("BLPOP did not return after push")
+	}
+
+	s.Queue("jobs").PushBack("y")
+	s.Queue("jobs").PushBack("z")
+	assertReply(t, consumer, []any{"jobs", "z"}, "BRPOP", "jobs", "1")
+
+	start := time.Now()
+	assertReply(t, consumer, []any{"jobs", "y"}, "BLPOP", "jobs", "0.01")
+	assertReply(t, consumer, nil, "BRPOP", "jobs", "0.01")
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected BRPOP to time out promptly, waited %v", elapsed)
+	}
+}
+
+func TestServer_DisconnectDuringBlockingPop(t *testing.T) {
+	_, addr := newTestServer(t)
+	producer := dial(t, addr)
+
+	for _, cmd := range []string{"BLPOP", "BRPOP"} {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		io.WriteString(conn, cmd+" jobs 0\r\n")
+		time.Sleep(20 * time.Millisecond)
+		conn.Close()
+		time.Sleep(20 * time.Millisecond)
+
+		do(t, producer, "RPUSH", "jobs", "x")
+		deadline := time.Now().Add(time.Second)
+		for do(t, producer, "LLEN", "jobs") != int64(1) {
+			if time.Now().After(deadline) {
+				t.Fatalf("%s: expected the item to stay queued after the client disconnected", cmd)
+			}
+			time.Sleep(time.Millisecond)
+		}
+		assertReply(t, producer, "x", "LPOP", "jobs")
+	}
+}
+
+func TestServer_CloseWakesBlockedPop(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	s := New()
+	go s.Serve(l)
+	c, err := Dial(l.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer c.Close()
+
+	done := make(chan struct{})
+	go func() {
+		c.Do("BLPOP", "jobs", "0")
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	s.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not wake the blocked BLPOP")
+	}
+	if err := s.Serve(l); err != ErrServerClosed {
+		t.Errorf("Expected ErrServerClosed, got %v", err)
+	}
+}
+
+func TestServer_InlineAndPipelined(t *testing.T) {
+	_, addr := newTestServer(t)
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer conn.Close()
+
+	io.WriteString(conn, "RPUSH jobs a b a\r\n*2\r\n$4\r\nLLEN\r\n$4\r\njobs\r\nping\r\nQUIT\r\n")
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	out, err := io.ReadAll(bufio.NewReader(conn))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := ":2\r\n:2\r\n+PONG\r\n+OK\r\n"
+	if string(out) != expected {
+		t.Errorf("Expected %q, got %q", expected, out)
+	}
+}
+
+func TestServer_ProtocolError(t *testing.T) {
+	_, addr := newTestServer(t)
+	for _, input := range []string{
+		"*1\r\n$x\r\n",
+		"*2\r\n*1\r\n$1\r\na\r\n",
+		"*1\r\n:1\r\n",
+		"*1\r\n$-1\r\n",
+		strings.Repeat("*1\r\n", 10000),
+	} {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		io.WriteString(conn, input)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		out, _ := io.ReadAll(conn)
+		conn.Close()
+		if !strings.HasPrefix(string(out), "-ERR resp: protocol error") {
+			t.Errorf("%.20q: expected a protocol error reply, got %q", input, out)
+		}
+	}
+}
+
+func TestNewWithOptions_Invalid(t *testing.T) {
+	opts := []uniqueue.Option{uniqueue.WithDebounce(time.Second), uniqueue.WithThrottle(time.Second)}
+	if _, err := NewWithOptions(opts...); !errors.Is(err, uniqueue.ErrInvalidOption) {
+		t.Errorf("Expected ErrInvalidOption, got %v", err)
+	}
+	if _, err := NewWithOptions(uniqueue.WithObserver[int](uniqueue.ObserverFuncs[int]{})); !errors.Is(err, uniqueue.ErrInvalidOption) {
+		t.Errorf("Expected ErrInvalidOption for an observer of another type, got %v", err)
+	}
+}
+
+func TestReadValue_Nesting(t *testing.T) {
+	nested := strings.Repeat("*1\r\n", maxDepth) + ":1\r\n"
+	if _, err := readValue(bufio.NewReader(strings.NewReader(nested))); err != nil {
+		t.Errorf("Expected %d nested arrays to be accepted, got %v", maxDepth, err)
+	}
+	tooDeep := "*1\r\n" + nested
+	if _, err := readValue(bufio.NewReader(strings.NewReader(tooDeep))); !errors.Is(err, errProtocol) {
+		t.Errorf("Expected a protocol error, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error is returned by Client when the server answers with an error status.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("server: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Client is a typed client for a Server.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient creates a client for the server at baseURL, for example
+// "http://localhost:8080". If httpClient is nil, http.DefaultClient is used.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    httpClient,
+	}
+}
+
+// Push adds item to the named queue unless it is already queued.
+func (c *Client) Push(ctx context.Context, queue, item string) error {
+	_, err := c.do(ctx, http.MethodPost, queue, "push", nil, ItemRequest{Item: item}, nil)
+	return err
+}
+
+// PushBatch adds items to the named queue in order, skipping duplicates.
+func (c *Client) PushBatch(ctx context.Context, queue string, items []string) error {
+	_, err := c.do(ctx, http.MethodPost, queue, "push-batch", nil, BatchRequest{Items: items}, nil)
+	return err
+}
+
+// Pop removes and returns the first item of the named queue. If the queue
+// is empty the server waits up to timeout for an item to arrive; the
+// boolean is false if none did.
+func (c *Client) Pop(ctx context.Context, queue string, timeout time.Duration) (string, bool, error) {
+	query := url.Values{}
+	if timeout > 0 {
+		query.Set("timeout", timeout.String())
+	}
+	var resp ItemResponse
+	status, err := c.do(ctx, http.MethodPost, queue, "pop", query, nil, &resp)
+	return resp.Item, err == nil && status == http.StatusOK, err
+}
+
+// Peek returns up to n items from the head of the named queue without
+// removing them.
+func (c *Client) Peek(ctx context.Context, queue string, n int) ([]string, error) {
+	var resp ItemsResponse
+	_, err := c.do(ctx, http.MethodGet, queue, "peek", url.Values{"n": {strconv.Itoa(n)}}, nil, &resp)
+	return resp.Items, err
+}
+
+// Remove deletes item from the named queue and reports whether it was
+// there.
+func (c *Client) Remove(ctx context.Context, queue, item string) (bool, error) {
+	var resp RemoveResponse
+	_, err := c.do(ctx, http.MethodPost, queue, "remove", nil, ItemRequest{Item: item}, &resp)
+	return resp.Removed, err
+}
+
+// Size returns the number of items in the named queue.
+func (c *Client) Size(ctx context.Context, queue string) (int, error) {
+	var resp SizeResponse
+	_, err := c.do(ctx, http.MethodGet, queue, "size", nil, nil, &resp)
+	return resp.Size, err
+}
+
+// Stats returns the size, oldest item age and lifetime counters of the
+// named queue.
+func (c *Client) Stats(ctx context.Context, queue string) (StatsResponse, error) {
+	var resp StatsResponse
+	_, err := c.do(ctx, http.MethodGet, queue, "stats", nil, nil, &resp)
+	return resp, err
+}
+
+// Queues returns the names of all queues on the server.
+func (c *Client) Queues(ctx context.Context) ([]string, error) {
+	var resp NamesResponse
+	_, err := c.request(ctx, http.MethodGet, c.baseURL+"/queues", nil, &resp)
+	return resp.Queues, err
+}
+
+func (c *Client) do(ctx context.Context, method, queue, op string, query url.Values, body, result any) (int, error) {
+	u := c.baseURL + "/queues/" + url.PathEscape(queue) + "/" + op
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return c.request(ctx, method, u, body, result)
+}
+
+// request sends body as JSON, decodes the response into result and returns
+// the response status. A 204 response leaves result untouched.
+func (c *Client) request(ctx context.Context, method, u string, body, result any) (int, error) {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		r = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var e ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Error == "" {
+			e.Error = http.StatusText(resp.StatusCode)
+		}
+		return resp.StatusCode, &Error{StatusCode: resp.StatusCode, Message: e.Error}
+	}
+	if resp.StatusCode == http.StatusNoContent || result == nil {
+		return resp.StatusCode, nil
+	}
+
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,359 @@
+// Package server exposes named uniqueue.Uniqueue instances over HTTP/JSON
+// so that several processes can share a deduplicating work queue.
+//
+// All endpoints live under /queues/{name}/ and queues are created by the
+// first push. Reading a queue that does not exist yet does not create it
+// and answers as if it were empty:
+//
+//	POST   /queues/{name}/push        {"item": "x"}
+//	POST   /queues/{name}/push-batch  {"items": ["x", "y"]}
+//	POST   /queues/{name}/pop?timeout=5s
+//	GET    /queues/{name}/peek?n=10
+//	POST   /queues/{name}/remove      {"item": "x"}
+//	GET    /queues/{name}/size
+//	GET    /queues/{name}/stats
+//	GET    /queues
+//
+// Pop long-polls for up to timeout (capped by Server.MaxWait) and answers
+// 204 No Content if the queue stayed empty. Errors are reported as
+// {"error": "..."} with a 4xx status. A long-polling pop on a queue that
+// does not exist yet waits for it to be created.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/realfatcat/uniqueue"
+)
+
+// DefaultMaxWait caps how long a pop request may long-poll.
+const DefaultMaxWait = 30 * time.Second
+
+// maxBodySize limits request bodies to keep a misbehaving client from
+// exhausting memory.
+const maxBodySize = 8 << 20
+
+// Server is an http.Handler serving a set of named queues of strings.
+type Server struct {
+	// MaxWait caps the pop timeout requested by clients.
+	MaxWait time.Duration
+
+	opts []uniqueue.Option
+	mux  *http.ServeMux
+
+	mu     sync.Mutex
+	queues map[string]*uniqueue.Uniqueue[string]
+	// added is closed and replaced whenever a queue is added, waking pops
+	// that wait for their queue to be created.
+	added chan struct{}
+}
+
+// New creates a server. opts are applied to every queue it creates.
+// It panics if the options are invalid; use NewWithOptions to get an
+// error instead.
+func New(opts ...uniqueue.Option) *Server {
+	s, err := NewWithOptions(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// NewWithOptions is like New but returns an error wrapping
+// uniqueue.ErrInvalidOption if the options are invalid, so that they are
+// rejected up front rather than when the first queue is created.
+func NewWithOptions(opts ...uniqueue.Option) (*Server, error) {
+	if _, err := uniqueue.NewUniqueueWithOptions[string](opts...); err != nil {
+		return nil, err
+	}
+	s := &Server{
+		MaxWait: DefaultMaxWait,
+		opts:    opts,
+		mux:     http.NewServeMux(),
+		queues:  make(map[string]*uniqueue.Uniqueue[string]),
+		added:   make(chan struct{}),
+	}
+	s.mux.HandleFunc("GET /queues", s.handleList)
+	s.mux.HandleFunc("POST /queues/{name}/push", s.handlePush)
+	s.mux.HandleFunc("POST /queues/{name}/push-batch", s.handlePushBatch)
+	s.mux.HandleFunc("POST /queues/{name}/pop", s.handlePop)
+	s.mux.HandleFunc("GET /queues/{name}/peek", s.handlePeek)
+	s.mux.HandleFunc("POST /queues/{name}/remove", s.handleRemove)
+	s.mux.HandleFunc("GET /queues/{name}/size", s.handleSize)
+	s.mux.HandleFunc("GET /queues/{name}/stats", s.handleStats)
+	return s, nil
+}
+
+// Queue returns the queue registered under name, creating it if needed.
+func (s *Server) Queue(name string) *uniqueue.Uniqueue[string] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.queues[name]
+	if !ok {
+		q = uniqueue.NewUniqueue[string](s.opts...)
+		s.add(name, q)
+	}
+	return q
+}
+
+// Lookup returns the queue registered under name without creating it.
+func (s *Server) Lookup(name string) (*uniqueue.Uniqueue[string], bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.queues[name]
+	return q, ok
+}
+
+// Register serves q under name, replacing any queue with the same name.
+func (s *Server) Register(name string, q *uniqueue.Uniqueue[string]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.add(name, q)
+}
+
+// add stores q under name and wakes waiting pops. Must be called with the
+// lock held.
+func (s *Server) add(name string, q *uniqueue.Uniqueue[string]) {
+	s.queues[name] = q
+	close(s.added)
+	s.added = make(chan struct{})
+}
+
+// await returns the queue registered under name, waiting for it to be
+// created until ctx is done.
+func (s *Server) await(ctx context.Context, name string) (*uniqueue.Uniqueue[string], error) {
+	for {
+		s.mu.Lock()
+		q, ok := s.queues[name]
+		added := s.added
+		s.mu.Unlock()
+		if ok {
+			return q, nil
+		}
+		select {
+		case <-added:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Names returns the names of all queues in sorted order.
+func (s *Server) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.queues))
+	for name := range s.queues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ItemRequest is the body of push and remove requests.
+type ItemRequest struct {
+	Item string `json:"item"`
+}
+
+// BatchRequest is the body of push-batch requests.
+type BatchRequest struct {
+	Items []string `json:"items"`
+}
+
+// ItemResponse is returned by a successful pop.
+type ItemResponse struct {
+	Item string `json:"item"`
+}
+
+// ItemsResponse is returned by peek.
+type ItemsResponse struct {
+	Items []string `json:"items"`
+}
+
+// RemoveResponse is returned by remove.
+type RemoveResponse struct {
+	Removed bool `json:"removed"`
+}
+
+// SizeResponse is returned by size.
+type SizeResponse struct {
+	Size int `json:"size"`
+}
+
+// StatsResponse is returned by stats.
+type StatsResponse struct {
+	Size int `json:"size"`
+	// OldestAge is the age of the oldest item in seconds.
+	OldestAge float64        `json:"oldest_age_seconds"`
+	Leased    int            `json:"leased"`
+	Stats     uniqueue.Stats `json:"stats"`
+}
+
+// NamesResponse is returned by the queue listing.
+type NamesResponse struct {
+	Queues []string `json:"queues"`
+}
+
+// ErrorResponse is the body of every error reply.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, NamesResponse{Queues: s.Names()})
+}
+
+func (s *Server) handlePush(w http.ResponseWriter, r *http.Request) {
+	var req ItemRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	s.Queue(r.PathValue("name")).PushBack(req.Item)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handlePushBatch(w http.ResponseWriter, r *http.Request) {
+	var req BatchRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	q := s.Queue(r.PathValue("name"))
+	for _, item := range req.Items {
+		q.PushBack(item)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handlePop(w http.ResponseWriter, r *http.Request) {
+	var timeout time.Duration
+	if v := r.URL.Query().Get("timeout"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			writeError(w, http.StatusBadRequest, "invalid timeout")
+			return
+		}
+		timeout = min(d, s.MaxWait)
+	}
+
+	name := r.PathValue("name")
+	if timeout == 0 {
+		q, ok := s.Lookup(name)
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		item, ok := q.PopHead()
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, http.StatusOK, ItemResponse{Item: item})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	q, err := s.await(ctx, name)
+	if err != nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	item, err := q.PopHeadWait(ctx)
+	if err != nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, ItemResponse{Item: item})
+}
+
+func (s *Server) handlePeek(w http.ResponseWriter, r *http.Request) {
+	n := 1
+	if v := r.URL.Query().Get("n"); v != "" {
+		var err error
+		n, err = strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid n")
+			return
+		}
+	}
+	items := []string{}
+	if q, ok := s.Lookup(r.PathValue("name")); ok {
+		items = q.PeekN(n)
+	}
+	writeJSON(w, http.StatusOK, ItemsResponse{Items: items})
+}
+
+func (s *Server) handleRemove(w http.ResponseWriter, r *http.Request) {
+	var req ItemRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	var removed bool
+	if q, ok := s.Lookup(r.PathValue("name")); ok {
+		removed = q.Remove(req.Item)
+	}
+	writeJSON(w, http.StatusOK, RemoveResponse{Removed: removed})
+}
+
+func (s *Server) handleSize(w http.ResponseWriter, r *http.Request) {
+	var size int
+	if q, ok := s.Lookup(r.PathValue("name")); ok {
+		size = q.Size()
+	}
+	writeJSON(w, http.StatusOK, SizeResponse{Size: size})
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	q, ok := s.Lookup(r.PathValue("name"))
+	if !ok {
+		writeJSON(w, http.StatusOK, StatsResponse{})
+		return
+	}
+	writeJSON(w, http.StatusOK, StatsResponse{
+		Size:      q.Size(),
+		OldestAge: q.OldestAge().Seconds(),
+		Leased:    q.Leased(),
+		Stats:     q.Stats(),
+	})
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeError(w, status, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, ErrorResponse{Error: msg})
+}
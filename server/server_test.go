@@ -0,0 +1,232 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/realfatcat/uniqueue"
+)
+
+func newTestServer(t *testing.T) (*Server, *Client) {
+	t.Helper()
+	s := New()
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return s, NewClient(ts.URL, ts.Client())
+}
+
+func TestServer_PushPop(t *testing.T) {
+	_, c := newTestServer(t)
+	ctx := context.Background()
+
+	for _, item := range []string{"a", "b", "a"} {
+		if err := c.Push(ctx, "jobs", item); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if err := c.PushBatch(ctx, "jobs", []string{"b", "c", "c"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if size, err := c.Size(ctx, "jobs"); err != nil || size != 3 {
+		t.Errorf("Expected (3, nil), got (%d, %v)", size, err)
+	}
+	if items, err := c.Peek(ctx, "jobs", 10); err != nil || !reflect.DeepEqual(items, []string{"a", "b", "c"}) {
+		t.Errorf("Expected ([a b c], nil), got (%v, %v)", items, err)
+	}
+
+	for _, expected := range []string{"a", "b", "c"} {
+		item, ok, err := c.Pop(ctx, "jobs", 0)
+		if err != nil || !ok || item != expected {
+			t.Errorf("Expected (%s, true, nil), got (%s, %v, %v)", expected, item, ok, err)
+		}
+	}
+	if item, ok, err := c.Pop(ctx, "jobs", 0); err != nil || ok {
+		t.Errorf("Expected empty pop, got (%s, %v, %v)", item, ok, err)
+	}
+}
+
+func TestServer_PopLongPoll(t *testing.T) {
+	_, c := newTestServer(t)
+	ctx := context.Background()
+
+	done := make(chan string, 1)
+	go func() {
+		item, _, _ := c.Pop(ctx, "jobs", 5*time.Second)
+		done <- item
+	}()
+
+	select {
+	case item := <-done:
+		t.Fatalf("Expected pop to wait on an empty queue, got %q", item)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if err := c.Push(ctx, "jobs", "x"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	select {
+	case item := <-done:
+		if item != "x" {
+			t.Errorf("Expected x, got %q", item)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Pop did not return after push")
+	}
+}
+
+func TestServer_PopTimeout(t *testing.T) {
+	s, c := newTestServer(t)
+	s.MaxWait = 20 * time.Millisecond
+
+	start := time.Now()
+	item, ok, err := c.Pop(context.Background(), "jobs", time.Hour)
+	if err != nil || ok {
+		t.Errorf("Expected empty pop, got (%s, %v, %v)", item, ok, err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected MaxWait to cap the timeout, waited %v", elapsed)
+	}
+}
+
+func TestServer_Remove(t *testing.T) {
+	_, c := newTestServer(t)
+	ctx := context.Background()
+	c.PushBatch(ctx, "jobs", []string{"a", "b"})
+
+	if removed, err := c.Remove(ctx, "jobs", "a"); err != nil || !removed {
+		t.Errorf("Expected (true, nil), got (%v, %v)", removed, err)
+	}
+	if removed, err := c.Remove(ctx, "jobs", "a"); err != nil || removed {
+		t.Errorf("Expected (false, nil), got (%v, %v)", removed, err)
+	}
+	if items, _ := c.Peek(ctx, "jobs", 10); !reflect.DeepEqual(items, []string{"b"}) {
+		t.Errorf("Expected [b], got %v", items)
+	}
+}
+
+func TestServer_Stats(t *testing.T) {
+	_, c := newTestServer(t)
+	ctx := context.Background()
+	c.PushBatch(ctx, "jobs", []string{"a", "b", "a"})
+	c.Pop(ctx, "jobs", 0)
+
+	stats, err := c.Stats(ctx, "jobs")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Size != 1 {
+		t.Errorf("Expected size 1, got %d", stats.Size)
+	}
+	if stats.Stats.Adds != 2 || stats.Stats.Duplicates != 1 || stats.Stats.Pops != 1 {
+		t.Errorf("Expected 2 adds, 1 duplicate and 1 pop, got %+v", stats.Stats)
+	}
+}
+
+func TestServer_Queues(t *testing.T) {
+	s, c := newTestServer(t)
+	ctx := context.Background()
+	c.Push(ctx, "b", "x")
+	c.Push(ctx, "a", "x")
+
+	names, err := c.Queues(ctx)
+	if err != nil || !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("Expected ([a b], nil), got (%v, %v)", names, err)
+	}
+	if s.Queue("a").Size() != 1 {
+		t.Errorf("Expected queue a to be shared with the server, got size %d", s.Queue("a").Size())
+	}
+}
+
+func TestServer_ReadsDoNotCreateQueues(t *testing.T) {
+	s, c := newTestServer(t)
+	ctx := context.Background()
+
+	if size, err := c.Size(ctx, "missing"); err != nil || size != 0 {
+		t.Errorf("Expected (0, nil), got (%d, %v)", size, err)
+	}
+	if items, err := c.Peek(ctx, "missing", 10); err != nil || len(items) != 0 {
+		t.Errorf("Expected no items, got (%v, %v)", items, err)
+	}
+	if stats, err := c.Stats(ctx, "missing"); err != nil || stats.Size != 0 {
+		t.Errorf("Expected empty stats, got (%+v, %v)", stats, err)
+	}
+	if removed, err := c.Remove(ctx, "missing", "a"); err != nil || removed {
+		t.Errorf("Expected (false, nil), got (%v, %v)", removed, err)
+	}
+	if _, ok, err := c.Pop(ctx, "missing", 0); err != nil || ok {
+		t.Errorf("Expected empty pop, got (%v, %v)", ok, err)
+	}
+	s.MaxWait = 10 * time.Millisecond
+	if _, ok, err := c.Pop(ctx, "missing", time.Second); err != nil || ok {
+		t.Errorf("Expected empty long-poll pop, got (%v, %v)", ok, err)
+	}
+
+	if names := s.Names(); len(names) != 0 {
+		t.Errorf("Expected reads not to create queues, got %v", names)
+	}
+	if _, ok := s.Lookup("missing"); ok {
+		t.Error("Expected Lookup not to create the queue")
+	}
+}
+
+func TestServer_BadRequests(t *testing.T) {
+	s, c := newTestServer(t)
+	ctx := context.Background()
+
+	var serr *Error
+	if _, err := c.Peek(ctx, "jobs", -1); !errors.As(err, &serr) || serr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for negative n, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		expected int
+	}{
+		{"malformed body", http.MethodPost, "/queues/jobs/push", "{", http.StatusBadRequest},
+		{"unknown field", http.MethodPost, "/queues/jobs/push", `{"value": "x"}`, http.StatusBadRequest},
+		{"invalid timeout", http.MethodPost, "/queues/jobs/pop?timeout=soon", "", http.StatusBadRequest},
+		{"wrong method", http.MethodGet, "/queues/jobs/push", "", http.StatusMethodNotAllowed},
+		{"unknown operation", http.MethodGet, "/queues/jobs/nope", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			if rec.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, rec.Code)
+			}
+		})
+	}
+}
+
+func TestNewWithOptions_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []uniqueue.Option
+	}{
+		{"observer of another type", []uniqueue.Option{uniqueue.WithObserver[int](uniqueue.ObserverFuncs[int]{})}},
+		{"debounce and throttle", []uniqueue.Option{uniqueue.WithDebounce(time.Second), uniqueue.WithThrottle(time.Second)}},
+		{"nil option", []uniqueue.Option{nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewWithOptions(tt.opts...); !errors.Is(err, uniqueue.ErrInvalidOption) {
+				t.Errorf("Expected ErrInvalidOption, got %v", err)
+			}
+		})
+	}
+	if _, err := NewWithOptions(uniqueue.WithSizeHint(8)); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
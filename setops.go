@@ -0,0 +1,142 @@
+package uniqueue
+
+// Clone returns a copy of the queue with the same items in the same order.
+// Enqueue timestamps and sequence numbers are preserved. Observers,
+// metrics, the change log, the size hint, automatic compaction and the
+// node pool are not copied.
+// Time complexity: O(n)
+func (u *UniqueueUnsafe[T]) Clone() *UniqueueUnsafe[T] {
+	c := u.derive()
+	c.seen = make(map[T]entry[T], len(u.seen))
+	for node := u.queue.head; node != nil; node = node.next {
+		c.copyEntry(node.value, u.seen[node.value])
+	}
+	for node := u.byAge.head; node != nil; node = node.next {
+		c.linkAge(node.value)
+	}
+	c.peak = len(c.seen)
+	c.seq = u.seq
+	return c
+}
+
+// Equal reports whether both queues hold the same items in the same order.
+// Time complexity: O(n)
+func (u *UniqueueUnsafe[T]) Equal(other *UniqueueUnsafe[T]) bool {
+	if u.Size() != other.Size() {
+		return false
+	}
+	for a, b := u.queue.head, other.queue.head; a != nil; a, b = a.next, b.next {
+		if a.value != b.value {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a new queue with the items of u followed by the items of
+// other that are not in u, each in their original order. Items keep the
+// enqueue timestamps and sequence numbers of the queue they are taken
+// from; like Clone, the result has none of the options of u.
+// Time complexity: O(n+m)
+func (u *UniqueueUnsafe[T]) Union(other *UniqueueUnsafe[T]) *UniqueueUnsafe[T] {
+	result := u.derive()
+	for node := u.queue.head; node != nil; node = node.next {
+		result.copyEntry(node.value, u.seen[node.value])
+	}
+	for node := other.queue.head; node != nil; node = node.next {
+		if !u.Contains(node.value) {
+			result.copyEntry(node.value, other.seen[node.value])
+		}
+	}
+	for node := u.byAge.head; node != nil; node = node.next {
+		result.linkAge(node.value)
+	}
+	for node := other.byAge.head; node != nil; node = node.next {
+		if !u.Contains(node.value) {
+			result.linkAge(node.value)
+		}
+	}
+	result.peak = len(result.seen)
+	result.seq = max(u.seq, other.seq)
+	return result
+}
+
+// Intersect returns a new queue with the items of u that are also in other,
+// in the order of u. Items keep their enqueue timestamps and sequence
+// numbers; like Clone, the result has none of the options of u.
+// Time complexity: O(n)
+func (u *UniqueueUnsafe[T]) Intersect(other *UniqueueUnsafe[T]) *UniqueueUnsafe[T] {
+	return u.filter(other.Contains)
+}
+
+// Difference returns a new queue with the items of u that are not in other,
+// in the order of u. Items keep their enqueue timestamps and sequence
+// numbers; like Clone, the result has none of the options of u.
+// Time complexity: O(n)
+func (u *UniqueueUnsafe[T]) Difference(other *UniqueueUnsafe[T]) *UniqueueUnsafe[T] {
+	return u.filter(func(item T) bool {
+		return !other.Contains(item)
+	})
+}
+
+// ToSlice returns the items of the queue from head to tail.
+// Time complexity: O(n)
+func (u *UniqueueUnsafe[T]) ToSlice() []T {
+	return u.queue.PeekN(u.queue.Size())
+}
+
+// ToSet returns the items of the queue as a set.
+// Time complexity: O(n)
+func (u *UniqueueUnsafe[T]) ToSet() map[T]struct{} {
+	set := make(map[T]struct{}, len(u.seen))
+	for item := range u.seen {
+		set[item] = struct{}{}
+	}
+	return set
+}
+
+// derive returns an empty queue sharing u's clock but none of its
+// observers, metrics or other options: the size hint, automatic
+// compaction and the node pool all take their defaults.
+func (u *UniqueueUnsafe[T]) derive() *UniqueueUnsafe[T] {
+	return &UniqueueUnsafe[T]{
+		queue: NewList[T](),
+		byAge: NewList[T](),
+		seen:  make(map[T]entry[T]),
+		now:   u.now,
+	}
+}
+
+func (u *UniqueueUnsafe[T]) filter(keep func(T) bool) *UniqueueUnsafe[T] {
+	result := u.derive()
+	for node := u.queue.head; node != nil; node = node.next {
+		if keep(node.value) {
+			result.copyEntry(node.value, u.seen[node.value])
+		}
+	}
+	for node := u.byAge.head; node != nil; node = node.next {
+		if result.Contains(node.value) {
+			result.linkAge(node.value)
+		}
+	}
+	result.peak = len(result.seen)
+	result.seq = u.seq
+	return result
+}
+
+// copyEntry appends item to the end of u with the enqueue timestamp and
+// sequence number of e, without notifying observers or metrics. The item
+// must then be linked into byAge with linkAge.
+func (u *UniqueueUnsafe[T]) copyEntry(item T, e entry[T]) {
+	e.node = u.queue.pushBack(item)
+	e.age = nil
+	u.seen[item] = e
+}
+
+// linkAge links a copied item into byAge. Linking the items of each source
+// oldest first keeps this linear overall, as in Merge.
+func (u *UniqueueUnsafe[T]) linkAge(item T) {
+	e := u.seen[item]
+	e.age = u.insertByAge(item, e.enqueued)
+	u.seen[item] = e
+}
@@ -0,0 +1,137 @@
+package uniqueue
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newUniqueueUnsafeOf[T comparable](items ...T) *UniqueueUnsafe[T] {
+	u := NewUniqueueUnsafe[T]()
+	for _, item := range items {
+		u.PushBack(item)
+	}
+	return u
+}
+
+func TestUniqueueUnsafe_Clone(t *testing.T) {
+	obs := &recordingObserver{}
+	u := NewUniqueueUnsafe[int](WithObserver[int](obs))
+	u.PushBack(1)
+	u.PushBack(2)
+
+	c := u.Clone()
+	if !c.Equal(u) {
+		t.Errorf("Expected clone to equal original, got %v", c.ToSlice())
+	}
+
+	// The clone is independent of the original.
+	c.PushBack(3)
+	c.PopHead()
+	assertOrder(t, u, []int{1, 2})
+	assertOrder(t, c, []int{2, 3})
+
+	if got := len(obs.Events()); got != 2 {
+		t.Errorf("Expected clone not to notify the original observers, got %d events", got)
+	}
+}
+
+func TestUniqueueUnsafe_Equal(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     []string
+		expected bool
+	}{
+		{"both empty", nil, nil, true},
+		{"same order", []string{"a", "b"}, []string{"a", "b"}, true},
+		{"different order", []string{"a", "b"}, []string{"b", "a"}, false},
+		{"different length", []string{"a"}, []string{"a", "b"}, false},
+		{"different items", []string{"a", "b"}, []string{"a", "c"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newUniqueueUnsafeOf(tt.a...)
+			b := newUniqueueUnsafeOf(tt.b...)
+			if got := a.Equal(b); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestUniqueueUnsafe_SetOperations(t *testing.T) {
+	a := newUniqueueUnsafeOf(1, 2, 3, 4)
+	b := newUniqueueUnsafeOf(5, 4, 2, 6)
+
+	assertOrder(t, a.Union(b), []int{1, 2, 3, 4, 5, 6})
+	assertOrder(t, b.Union(a), []int{5, 4, 2, 6, 1, 3})
+	assertOrder(t, a.Intersect(b), []int{2, 4})
+	assertOrder(t, b.Intersect(a), []int{4, 2})
+	assertOrder(t, a.Difference(b), []int{1, 3})
+	assertOrder(t, b.Difference(a), []int{5, 6})
+
+	// Operands are left untouched.
+	assertOrder(t, a, []int{1, 2, 3, 4})
+	assertOrder(t, b, []int{5, 4, 2, 6})
+
+	empty := NewUniqueueUnsafe[int]()
+	assertOrder(t, a.Intersect(empty), []int{})
+	assertOrder(t, a.Difference(empty), []int{1, 2, 3, 4})
+}
+
+func TestUniqueueUnsafe_SetOperations_Timestamps(t *testing.T) {
+	clock := newFakeClock()
+	a := NewUniqueueUnsafe[int](withClock(clock.Now))
+	b := NewUniqueueUnsafe[int](withClock(clock.Now))
+	b.PushBack(5)
+	clock.Advance(time.Second)
+	a.PushBack(1)
+	a.PushBack(2)
+	clock.Advance(time.Second)
+	b.PushBack(2)
+	a.PushFront(3)
+	clock.Advance(time.Second)
+
+	tests := []struct {
+		name     string
+		result   *UniqueueUnsafe[int]
+		expected time.Duration
+	}{
+		{"Clone", a.Clone(), 2 * time.Second},
+		{"Union", a.Union(b), 3 * time.Second},
+		{"Intersect", a.Intersect(b), 2 * time.Second},
+		{"Difference", a.Difference(b), 2 * time.Second},
+		{"Difference of b", b.Difference(a), 3 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := uniqueueInvariants(tt.result); err != nil {
+				t.Fatalf("Broken invariant: %v", err)
+			}
+			if age := tt.result.OldestAge(); age != tt.expected {
+				t.Errorf("Expected the oldest item to keep its age of %v, got %v", tt.expected, age)
+			}
+		})
+	}
+
+	// A shared item keeps the timestamp of the queue it is taken from.
+	u := a.Union(b)
+	u.PopHead()
+	u.PopHead()
+	u.PopHead()
+	if age := u.OldestAge(); age != 3*time.Second {
+		t.Errorf("Expected 3s for the remaining item of b, got %v", age)
+	}
+}
+
+func TestUniqueueUnsafe_ToSliceToSet(t *testing.T) {
+	u := newUniqueueUnsafeOf("x", "y", "z")
+
+	if got := u.ToSlice(); !reflect.DeepEqual(got, []string{"x", "y", "z"}) {
+		t.Errorf("Expected [x y z], got %v", got)
+	}
+	expected := map[string]struct{}{"x": {}, "y": {}, "z": {}}
+	if got := u.ToSet(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
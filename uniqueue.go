@@ -1,7 +1,10 @@
 package uniqueue
 
 import (
+	"context"
+	"math/rand/v2"
 	"sync"
+	"time"
 )
 
 // Uniqueue is a thread-safe generic unique queue that enforces uniqueness
@@ -10,23 +13,85 @@ import (
 type Uniqueue[T comparable] struct {
 	mu       sync.RWMutex
 	uniqueue *UniqueueUnsafe[T]
+	// changed is closed and cleared on every mutation to wake up waiters.
+	// It is created lazily by the first waiter.
+	changed chan struct{}
+	leases  leases[T]
+	dead    deadLetters[T]
+	delays  delays[T]
+	// id tells followers whether sequence numbers they saw came from
+	// this queue.
+	id uint64
 }
 
 // NewUniqueue creates and returns a new empty thread-safe unique queue.
-func NewUniqueue[T comparable]() *Uniqueue[T] {
-	return &Uniqueue[T]{
-		uniqueue: NewUniqueueUnsafe[T](),
+// Observers registered through opts are called while the queue's lock is
+// held; see Observer for the exact guarantees.
+// It panics if the options are invalid; use NewUniqueueWithOptions to get
+// an error instead.
+func NewUniqueue[T comparable](opts ...Option) *Uniqueue[T] {
+	u, err := NewUniqueueWithOptions[T](opts...)
+	if err != nil {
+		panic(err)
 	}
+	return u
+}
+
+// NewUniqueueWithOptions is like NewUniqueue but returns an error wrapping
+// ErrInvalidOption if the options are invalid.
+func NewUniqueueWithOptions[T comparable](opts ...Option) (*Uniqueue[T], error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	unsafe, err := newUniqueueUnsafe[T](o)
+	if err != nil {
+		return nil, err
+	}
+	return &Uniqueue[T]{
+		uniqueue: unsafe,
+		leases:   newLeases[T](o),
+		dead:     newDeadLetters[T](),
+		delays:   newDelays[T](o),
+		id:       rand.Uint64(),
+	}, nil
 }
 
 // PushBack adds an item to the end of the queue if it doesn't already exist.
-// If the item is already in the queue, this operation does nothing.
+// If the item is already in the queue or currently leased, this operation
+// does nothing. With WithDebounce or WithThrottle the item may only become
+// visible later.
 // Time complexity: O(1)
 func (u *Uniqueue[T]) PushBack(item T) {
+	u.push(item, false)
+}
+
+// PushFront adds an item to the head of the queue if it doesn't already
+// exist. If the item is already in the queue or currently leased, this
+// operation does nothing. With WithDebounce or WithThrottle the item may
+// only become visible later.
+// Time complexity: O(1)
+func (u *Uniqueue[T]) PushFront(item T) {
+	u.push(item, true)
+}
+
+func (u *Uniqueue[T]) push(item T, front bool) {
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
-	u.uniqueue.PushBack(item)
+	if u.leases.holds(item) {
+		u.uniqueue.duplicate(item)
+		return
+	}
+	if u.delays.enabled() && !u.uniqueue.Contains(item) && !u.holdPush(item, front) {
+		return
+	}
+	if front {
+		u.uniqueue.PushFront(item)
+	} else {
+		u.uniqueue.PushBack(item)
+	}
+	u.broadcast()
 }
 
 // PopHead removes and returns the first item from the queue.
@@ -36,7 +101,209 @@ func (u *Uniqueue[T]) PopHead() (T, bool) {
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
-	return u.uniqueue.PopHead()
+	return u.pop(u.uniqueue.PopHead)
+}
+
+// PopTail removes and returns the last item from the queue.
+// Returns the zero value and false if the queue is empty.
+// Time complexity: O(1)
+func (u *Uniqueue[T]) PopTail() (T, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.pop(u.uniqueue.PopTail)
+}
+
+// PopHeadWait removes and returns the first item from the queue, blocking
+// until one is available or ctx is done, in which case ctx.Err() is
+// returned.
+func (u *Uniqueue[T]) PopHeadWait(ctx context.Context) (T, error) {
+	return u.popWait(ctx, u.uniqueue.PopHead)
+}
+
+// PopTailWait removes and returns the last item from the queue, blocking
+// until one is available or ctx is done, in which case ctx.Err() is
+// returned.
+func (u *Uniqueue[T]) PopTailWait(ctx context.Context) (T, error) {
+	return u.popWait(ctx, u.uniqueue.PopTail)
+}
+
+func (u *Uniqueue[T]) popWait(ctx context.Context, take func() (T, bool)) (T, error) {
+	var item T
+	err := u.wait(ctx, func() bool {
+		var ok bool
+		item, ok = u.pop(take)
+		return ok
+	})
+	return item, err
+}
+
+// pop takes an item from one end of the queue and forgets its delivery
+// attempts. Must be called with the lock held.
+func (u *Uniqueue[T]) pop(take func() (T, bool)) (T, bool) {
+	item, ok := take()
+	if ok {
+		delete(u.leases.attempts, item)
+		u.broadcast()
+	}
+	return item, ok
+}
+
+// Remove deletes item from the queue wherever it is, or cancels its
+// pending push.
+// Returns false if the item is neither in the queue nor pending.
+// Time complexity: O(1)
+func (u *Uniqueue[T]) Remove(item T) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if !u.uniqueue.Remove(item) {
+		return u.delays.cancel(item)
+	}
+	delete(u.leases.attempts, item)
+	u.broadcast()
+	return true
+}
+
+// Clear removes all items from the queue. Leased and dead-lettered items
+// are not affected.
+// Time complexity: O(n) with observers, O(1) otherwise
+func (u *Uniqueue[T]) Clear() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.uniqueue.Clear()
+	u.forgetAttempts()
+	u.delays.cancelAll()
+	u.broadcast()
+}
+
+// Retain keeps only the items for which pred returns true, preserving
+// their order, and returns the number of items removed. pred is called
+// with the lock held and must not call back into the queue.
+// Time complexity: O(n)
+func (u *Uniqueue[T]) Retain(pred func(T) bool) int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	n := u.uniqueue.Retain(pred)
+	if n > 0 {
+		u.forgetAttempts()
+		u.broadcast()
+	}
+	return n
+}
+
+// Replace substitutes old with new, keeping its position in the queue.
+// Returns false if old is not in the queue, or if new is already queued or
+// leased (unless it is old itself), since that would break uniqueness.
+// Time complexity: O(1)
+func (u *Uniqueue[T]) Replace(old, new T) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if old != new && u.leases.holds(new) {
+		return false
+	}
+	if !u.uniqueue.Replace(old, new) {
+		return false
+	}
+	if old != new {
+		delete(u.leases.attempts, old)
+		u.broadcast()
+	}
+	return true
+}
+
+// forgetAttempts drops delivery counts of items that are no longer queued.
+// Must be called with the lock held.
+func (u *Uniqueue[T]) forgetAttempts() {
+	for item := range u.leases.attempts {
+		if !u.leases.holds(item) && !u.uniqueue.Contains(item) {
+			delete(u.leases.attempts, item)
+		}
+	}
+}
+
+// MoveToFront moves item to the head of the queue so it is popped next.
+// Returns false if the item is not in the queue.
+// Time complexity: O(1)
+func (u *Uniqueue[T]) MoveToFront(item T) bool {
+	return u.move(func() bool { return u.uniqueue.MoveToFront(item) })
+}
+
+// MoveToBack moves item to the tail of the queue.
+// Returns false if the item is not in the queue.
+// Time complexity: O(1)
+func (u *Uniqueue[T]) MoveToBack(item T) bool {
+	return u.move(func() bool { return u.uniqueue.MoveToBack(item) })
+}
+
+// MoveBefore moves item directly in front of mark.
+// Returns false if either item is not in the queue. Moving an item
+// relative to itself leaves the queue unchanged.
+// Time complexity: O(1)
+func (u *Uniqueue[T]) MoveBefore(item, mark T) bool {
+	return u.move(func() bool { return u.uniqueue.MoveBefore(item, mark) })
+}
+
+// MoveAfter moves item directly behind mark.
+// Returns false if either item is not in the queue. Moving an item
+// relative to itself leaves the queue unchanged.
+// Time complexity: O(1)
+func (u *Uniqueue[T]) MoveAfter(item, mark T) bool {
+	return u.move(func() bool { return u.uniqueue.MoveAfter(item, mark) })
+}
+
+func (u *Uniqueue[T]) move(fn func() bool) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if !fn() {
+		return false
+	}
+	u.broadcast()
+	return true
+}
+
+// Peek returns the first item without removing it.
+// Returns the zero value and false if the queue is empty.
+// Time complexity: O(1)
+func (u *Uniqueue[T]) Peek() (T, bool) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.uniqueue.Peek()
+}
+
+// PeekTail returns the last item without removing it.
+// Returns the zero value and false if the queue is empty.
+// Time complexity: O(1)
+func (u *Uniqueue[T]) PeekTail() (T, bool) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.uniqueue.PeekTail()
+}
+
+// PeekN returns up to n items from the head of the queue, in order,
+// without removing them.
+// Time complexity: O(n)
+func (u *Uniqueue[T]) PeekN(n int) []T {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.uniqueue.PeekN(n)
+}
+
+// IndexOf returns the zero-based position of item counted from the head.
+// Returns -1 and false if the item is not in the queue.
+// Time complexity: O(i) where i is the returned position
+func (u *Uniqueue[T]) IndexOf(item T) (int, bool) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.uniqueue.IndexOf(item)
 }
 
 // Size returns the number of unique items in the queue.
@@ -66,3 +333,119 @@ func (u *Uniqueue[T]) IsEmpty() bool {
 
 	return u.uniqueue.IsEmpty()
 }
+
+// OldestAge returns how long the longest-waiting item has been in the
+// queue, wherever it is, or zero if the queue is empty. Items returned by
+// an expired or nacked lease count from when they were first queued.
+// Time complexity: O(1)
+func (u *Uniqueue[T]) OldestAge() time.Duration {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.uniqueue.OldestAge()
+}
+
+// UpdateMetrics reports the current depth and the age of the oldest item to
+// the configured Metrics provider. Both are refreshed automatically on every
+// change; call this periodically to keep the unfinished-work age current
+// while the queue is idle.
+func (u *Uniqueue[T]) UpdateMetrics() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.uniqueue.UpdateMetrics()
+}
+
+// Options returns the configuration the queue was created with.
+func (u *Uniqueue[T]) Options() Config {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	c := u.uniqueue.Options()
+	c.LeaseRequeue = u.leases.requeue
+	c.MaxDeliveries = u.leases.maxDeliveries
+	c.Debounce = u.delays.debounce
+	c.Throttle = u.delays.throttle
+	return c
+}
+
+// Stats returns the lifetime counters of the queue.
+func (u *Uniqueue[T]) Stats() Stats {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.uniqueue.Stats()
+}
+
+// DebugInfo returns a snapshot of the queue including up to n items from
+// the head. It implements DebugSource.
+func (u *Uniqueue[T]) DebugInfo(n int) DebugInfo {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	items := u.uniqueue.PeekN(n)
+	info := DebugInfo{
+		Size:      u.uniqueue.Size(),
+		OldestAge: u.uniqueue.OldestAge().Seconds(),
+		Items:     make([]any, len(items)),
+		Stats:     u.uniqueue.Stats(),
+	}
+	for i, item := range items {
+		info.Items[i] = item
+	}
+	return info
+}
+
+// WaitEmpty blocks until the queue is empty or ctx is done.
+// Returns ctx.Err() if the context ends first.
+func (u *Uniqueue[T]) WaitEmpty(ctx context.Context) error {
+	return u.wait(ctx, u.uniqueue.IsEmpty)
+}
+
+// WaitContains blocks until item is in the queue or ctx is done.
+// Returns ctx.Err() if the context ends first.
+func (u *Uniqueue[T]) WaitContains(ctx context.Context, item T) error {
+	return u.wait(ctx, func() bool {
+		return u.uniqueue.Contains(item)
+	})
+}
+
+// WaitGone blocks until item is not in the queue or ctx is done.
+// Returns ctx.Err() if the context ends first.
+func (u *Uniqueue[T]) WaitGone(ctx context.Context, item T) error {
+	return u.wait(ctx, func() bool {
+		return !u.uniqueue.Contains(item)
+	})
+}
+
+// wait blocks until cond, evaluated with the lock held, returns true.
+// The condition is rechecked after every mutation of the queue.
+func (u *Uniqueue[T]) wait(ctx context.Context, cond func() bool) error {
+	for {
+		u.mu.Lock()
+		if cond() {
+			u.mu.Unlock()
+			return nil
+		}
+		if u.changed == nil {
+			u.changed = make(chan struct{})
+		}
+		changed := u.changed
+		u.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// broadcast wakes up all goroutines blocked in wait.
+// Must be called with the lock held.
+func (u *Uniqueue[T]) broadcast() {
+	if u.changed != nil {
+		close(u.changed)
+		u.changed = nil
+	}
+}
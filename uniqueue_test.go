@@ -1,8 +1,10 @@
 package uniqueue
 
 import (
+	"context"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestNewUniqueue(t *testing.T) {
@@ -266,3 +268,257 @@ func TestUniqueue_GenericTypes(t *testing.T) {
 		}
 	})
 }
+
+func TestUniqueue_Remove(t *testing.T) {
+	u := NewUniqueue[string]()
+	u.PushBack("a")
+	u.PushBack("b")
+
+	if !u.Remove("a") {
+		t.Error("Expected Remove to return true")
+	}
+	if u.Remove("missing") {
+		t.Error("Expected Remove to return false for missing item")
+	}
+	val, ok := u.PopHead()
+	if !ok || val != "b" {
+		t.Errorf("Expected ('b', true), got (%s, %v)", val, ok)
+	}
+}
+
+func TestUniqueue_WaitEmpty(t *testing.T) {
+	u := NewUniqueue[int]()
+
+	if err := u.WaitEmpty(context.Background()); err != nil {
+		t.Errorf("Expected nil for empty queue, got %v", err)
+	}
+
+	u.PushBack(1)
+	u.PushBack(2)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- u.WaitEmpty(context.Background())
+	}()
+
+	u.PopHead()
+	select {
+	case err := <-done:
+		t.Fatalf("Expected WaitEmpty to block while items remain, got %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	u.Remove(2)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitEmpty did not return after the queue was drained")
+	}
+}
+
+func TestUniqueue_WaitContains(t *testing.T) {
+	u := NewUniqueue[string]()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- u.WaitContains(context.Background(), "b")
+	}()
+
+	u.PushBack("a")
+	u.PushBack("b")
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitContains did not return after the item was pushed")
+	}
+}
+
+func TestUniqueue_WaitGone(t *testing.T) {
+	u := NewUniqueue[string]()
+	u.PushBack("a")
+	u.PushBack("b")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- u.WaitGone(context.Background(), "b")
+	}()
+
+	u.PopHead()
+	u.PopHead()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitGone did not return after the item was popped")
+	}
+}
+
+func TestUniqueue_Wait_ContextCanceled(t *testing.T) {
+	u := NewUniqueue[int]()
+	u.PushBack(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := u.WaitEmpty(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+	if err := u.WaitContains(ctx, 2); err != context.DeadlineExceeded {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestUniqueue_Peek(t *testing.T) {
+	u := NewUniqueue[int]()
+	u.PushBack(1)
+	u.PushBack(2)
+	u.PushBack(3)
+
+	if val, ok := u.Peek(); !ok || val != 1 {
+		t.Errorf("Expected (1, true), got (%d, %v)", val, ok)
+	}
+	if val, ok := u.PeekTail(); !ok || val != 3 {
+		t.Errorf("Expected (3, true), got (%d, %v)", val, ok)
+	}
+	if got := u.PeekN(5); len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Errorf("Expected [1 2 3], got %v", got)
+	}
+	if index, ok := u.IndexOf(2); !ok || index != 1 {
+		t.Errorf("Expected (1, true), got (%d, %v)", index, ok)
+	}
+	if u.Size() != 3 {
+		t.Errorf("Expected size 3, got %d", u.Size())
+	}
+}
+
+func TestUniqueue_Move(t *testing.T) {
+	u := NewUniqueue[string]()
+	u.PushBack("a")
+	u.PushBack("b")
+	u.PushBack("c")
+
+	if !u.MoveToFront("c") {
+		t.Error("Expected MoveToFront to return true")
+	}
+	if !u.MoveAfter("a", "b") {
+		t.Error("Expected MoveAfter to return true")
+	}
+	if u.MoveToBack("missing") {
+		t.Error("Expected MoveToBack to return false for missing item")
+	}
+	if got := u.PeekN(3); len(got) != 3 || got[0] != "c" || got[1] != "b" || got[2] != "a" {
+		t.Errorf("Expected [c b a], got %v", got)
+	}
+}
+
+func TestUniqueue_ClearRetainReplace(t *testing.T) {
+	u := NewUniqueue[int]()
+	for i := 1; i <= 4; i++ {
+		u.PushBack(i)
+	}
+
+	if removed := u.Retain(func(v int) bool { return v > 2 }); removed != 2 {
+		t.Errorf("Expected 2 removed, got %d", removed)
+	}
+	if !u.Replace(3, 30) {
+		t.Error("Expected Replace to return true")
+	}
+	if got := u.PeekN(10); len(got) != 2 || got[0] != 30 || got[1] != 4 {
+		t.Errorf("Expected [30 4], got %v", got)
+	}
+
+	u.Clear()
+	if !u.IsEmpty() {
+		t.Error("Expected queue to be empty after Clear")
+	}
+	if err := u.WaitEmpty(context.Background()); err != nil {
+		t.Errorf("Expected WaitEmpty to succeed, got %v", err)
+	}
+}
+
+func TestUniqueue_Replace_Leased(t *testing.T) {
+	u := NewUniqueue[string]()
+	u.PushBack("a")
+	u.PushBack("b")
+	_, id, _ := u.Lease(context.Background(), time.Minute)
+	defer u.Ack(id)
+
+	if u.Replace("b", "a") {
+		t.Error("Expected Replace to refuse an item that is currently leased")
+	}
+}
+
+func TestUniqueue_PopHeadWait(t *testing.T) {
+	u := NewUniqueue[int]()
+	u.PushBack(1)
+
+	if val, err := u.PopHeadWait(context.Background()); err != nil || val != 1 {
+		t.Errorf("Expected (1, nil), got (%d, %v)", val, err)
+	}
+
+	done := make(chan int, 1)
+	go func() {
+		val, _ := u.PopHeadWait(context.Background())
+		done <- val
+	}()
+	select {
+	case val := <-done:
+		t.Fatalf("Expected PopHeadWait to block on empty queue, got %d", val)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	u.PushBack(2)
+	select {
+	case val := <-done:
+		if val != 2 {
+			t.Errorf("Expected 2, got %d", val)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PopHeadWait did not return after push")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := u.PopHeadWait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestUniqueue_PushFrontPopTail(t *testing.T) {
+	u := NewUniqueue[int]()
+	u.PushBack(2)
+	u.PushFront(1)
+	u.PushFront(2)
+
+	if val, ok := u.PopTail(); !ok || val != 2 {
+		t.Errorf("Expected (2, true), got (%d, %v)", val, ok)
+	}
+	if val, ok := u.PopTail(); !ok || val != 1 {
+		t.Errorf("Expected (1, true), got (%d, %v)", val, ok)
+	}
+
+	done := make(chan int, 1)
+	go func() {
+		val, _ := u.PopTailWait(context.Background())
+		done <- val
+	}()
+	u.PushFront(3)
+	select {
+	case val := <-done:
+		if val != 3 {
+			t.Errorf("Expected 3, got %d", val)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PopTailWait did not return after push")
+	}
+}
@@ -1,19 +1,108 @@
 package uniqueue
 
+import "time"
+
 // UniqueueUnsafe is a non-thread-safe generic unique queue that enforces
 // uniqueness of items. Duplicate items are automatically ignored when added.
 // This type should only be used from a single goroutine.
 type UniqueueUnsafe[T comparable] struct {
-	queue *Queue[T]
-	seen  map[T]struct{}
+	queue *List[T]
+	// byAge holds the items in order of their enqueue time, which differs
+	// from queue order once items are pushed to the front, moved or
+	// requeued. ageHint is the node of the last item placed out of
+	// order, where the next such item is likely to belong.
+	byAge     *List[T]
+	ageHint   *node[T]
+	seen      map[T]entry[T]
+	observers []Observer[T]
+	metrics   Metrics
+	now       func() time.Time
+	stats     Stats
+
+	// sizeHint is the capacity the index is created and compacted with.
+	sizeHint int
+	// autoCompact is the peak size from which the index is compacted
+	// automatically; zero disables it. peak is the largest size since
+	// the index was last rebuilt.
+	autoCompact int
+	peak        int
+
+	// seq counts mutations; changes logs the most recent ones if enabled.
+	seq     uint64
+	changes *changeLog[T]
+}
+
+// Stats holds lifetime counters of a queue.
+type Stats struct {
+	Adds       uint64 `json:"adds"`
+	Duplicates uint64 `json:"duplicates"`
+	Pops       uint64 `json:"pops"`
+	Removes    uint64 `json:"removes"`
+}
+
+// entry indexes a queued item: the node holding it in queue and in byAge,
+// and when and at which sequence number it was enqueued.
+type entry[T comparable] struct {
+	node     *node[T]
+	age      *node[T]
+	enqueued time.Time
+	seq      uint64
 }
 
 // NewUniqueueUnsafe creates and returns a new empty unique queue.
 // This type is not thread-safe and should only be used from one goroutine.
-func NewUniqueueUnsafe[T comparable]() *UniqueueUnsafe[T] {
+// Observers registered through opts are called synchronously.
+// It panics if the options are invalid; use NewUniqueueUnsafeWithOptions
+// to get an error instead.
+func NewUniqueueUnsafe[T comparable](opts ...Option) *UniqueueUnsafe[T] {
+	u, err := NewUniqueueUnsafeWithOptions[T](opts...)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// NewUniqueueUnsafeWithOptions is like NewUniqueueUnsafe but returns an
+// error wrapping ErrInvalidOption if the options are invalid, including
+// options that only apply to Uniqueue.
+func NewUniqueueUnsafeWithOptions[T comparable](opts ...Option) (*UniqueueUnsafe[T], error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if o.uniqueueOption != "" {
+		return nil, invalid("%s only applies to Uniqueue", o.uniqueueOption)
+	}
+	return newUniqueueUnsafe[T](o)
+}
+
+func newUniqueueUnsafe[T comparable](o *options) (*UniqueueUnsafe[T], error) {
+	observers, err := observersFor[T](o)
+	if err != nil {
+		return nil, err
+	}
 	return &UniqueueUnsafe[T]{
-		queue: NewQueue[T](),
-		seen:  make(map[T]struct{}),
+		queue:       newList[T](o),
+		byAge:       newList[T](o),
+		seen:        make(map[T]entry[T], o.sizeHint),
+		observers:   observers,
+		metrics:     o.metrics,
+		now:         o.now,
+		sizeHint:    o.sizeHint,
+		autoCompact: o.autoCompact,
+		changes:     newChangeLog[T](o.changeLog),
+	}, nil
+}
+
+func newList[T any](o *options) *List[T] {
+	l := NewList[T]()
+	l.SetNodePool(o.nodePool)
+	return l
+}
+
+func (u *UniqueueUnsafe[T]) notify(kind eventKind, item T) {
+	for _, obs := range u.observers {
+		event[T]{kind: kind, item: item}.dispatch(obs)
 	}
 }
 
@@ -21,24 +110,368 @@ func NewUniqueueUnsafe[T comparable]() *UniqueueUnsafe[T] {
 // If the item is already in the queue, this operation does nothing.
 // Time complexity: O(1)
 func (u *UniqueueUnsafe[T]) PushBack(item T) {
+	u.push(item, u.queue.pushBack, ChangePushBack, time.Time{})
+}
+
+// PushFront adds an item to the head of the queue if it doesn't already
+// exist. If the item is already in the queue, this operation does nothing.
+// Time complexity: O(1)
+func (u *UniqueueUnsafe[T]) PushFront(item T) {
+	u.push(item, u.queue.pushFront, ChangePushFront, time.Time{})
+}
+
+// pushEnqueued pushes an item that returns to the queue, such as an item
+// whose lease ended, keeping enqueued as its enqueue time.
+func (u *UniqueueUnsafe[T]) pushEnqueued(item T, front bool, enqueued time.Time) {
+	if front {
+		u.push(item, u.queue.pushFront, ChangePushFront, enqueued)
+	} else {
+		u.push(item, u.queue.pushBack, ChangePushBack, enqueued)
+	}
+}
+
+// push inserts item with insert unless it is queued. A zero enqueued
+// means now.
+func (u *UniqueueUnsafe[T]) push(item T, insert func(T) *node[T], kind ChangeKind, enqueued time.Time) {
 	if _, ok := u.seen[item]; ok {
+		u.duplicate(item)
 		return
 	}
-	u.seen[item] = struct{}{}
-	u.queue.PushBack(item)
+	var zero T
+	u.record(kind, item, zero)
+	now := u.now()
+	if enqueued.IsZero() {
+		enqueued = now
+	}
+	u.seen[item] = entry[T]{
+		node:     insert(item),
+		age:      u.insertByAge(item, enqueued),
+		enqueued: enqueued,
+		seq:      u.seq,
+	}
+	u.peak = max(u.peak, len(u.seen))
+	u.stats.Adds++
+	if u.metrics != nil {
+		u.metrics.Add()
+		u.reportDepth(now)
+	}
+	u.notify(eventPush, item)
+}
+
+// duplicate records a push of item that was rejected as a duplicate.
+func (u *UniqueueUnsafe[T]) duplicate(item T) {
+	u.stats.Duplicates++
+	if u.metrics != nil {
+		u.metrics.Duplicate()
+	}
+	u.notify(eventDuplicate, item)
 }
 
 // PopHead removes and returns the first item from the queue.
 // Returns the zero value and false if the queue is empty.
 // Time complexity: O(1)
 func (u *UniqueueUnsafe[T]) PopHead() (T, bool) {
-	item, ok := u.queue.PopHead()
+	return u.pop(u.queue.PopHead())
+}
+
+// PopTail removes and returns the last item from the queue.
+// Returns the zero value and false if the queue is empty.
+// Time complexity: O(1)
+func (u *UniqueueUnsafe[T]) PopTail() (T, bool) {
+	return u.pop(u.queue.PopTail())
+}
+
+// pop records the removal of item from either end of the queue.
+func (u *UniqueueUnsafe[T]) pop(item T, ok bool) (T, bool) {
 	if ok {
+		var zero T
+		u.record(ChangePop, item, zero)
+		e := u.seen[item]
+		u.removeByAge(e)
 		delete(u.seen, item)
+		u.stats.Pops++
+		if u.metrics != nil {
+			now := u.now()
+			u.metrics.Pop(now.Sub(e.enqueued))
+			u.reportDepth(now)
+		}
+		u.notify(eventPop, item)
+		u.maybeCompact()
 	}
 	return item, ok
 }
 
+// Remove deletes item from the queue wherever it is.
+// Returns false if the item is not in the queue.
+// Time complexity: O(1)
+func (u *UniqueueUnsafe[T]) Remove(item T) bool {
+	e, ok := u.seen[item]
+	if !ok {
+		return false
+	}
+	var zero T
+	u.record(ChangeRemove, item, zero)
+	u.queue.unlink(e.node)
+	u.queue.release(e.node)
+	u.removeByAge(e)
+	delete(u.seen, item)
+	u.stats.Removes++
+	if u.metrics != nil {
+		u.reportDepth(u.now())
+	}
+	u.notify(eventRemove, item)
+	u.maybeCompact()
+	return true
+}
+
+// Stats returns the lifetime counters of the queue.
+func (u *UniqueueUnsafe[T]) Stats() Stats {
+	return u.stats
+}
+
+// OldestAge returns how long the longest-waiting item has been in the
+// queue, wherever it is, or zero if the queue is empty.
+// Time complexity: O(1)
+func (u *UniqueueUnsafe[T]) OldestAge() time.Duration {
+	return u.oldestAge(u.now())
+}
+
+func (u *UniqueueUnsafe[T]) oldestAge(now time.Time) time.Duration {
+	if u.byAge.head == nil {
+		return 0
+	}
+	return now.Sub(u.seen[u.byAge.head.value].enqueued)
+}
+
+// headEnqueued returns when the item at the head was enqueued, or the zero
+// time if the queue is empty.
+func (u *UniqueueUnsafe[T]) headEnqueued() time.Time {
+	if u.queue.head == nil {
+		return time.Time{}
+	}
+	return u.seen[u.queue.head.value].enqueued
+}
+
+// insertByAge links item into byAge behind every item enqueued no later
+// than enqueued and returns its node. New items go to the tail in O(1).
+// Backdated ones are placed by a scan from the previous backdated item, so
+// a run of them in either age order, such as requeues of expired leases,
+// costs O(1) each.
+func (u *UniqueueUnsafe[T]) insertByAge(item T, enqueued time.Time) *node[T] {
+	n := u.byAge.alloc(item)
+	tail := u.byAge.tail
+	if tail == nil || !enqueued.Before(u.seen[tail.value].enqueued) {
+		u.byAge.linkBack(n)
+		return n
+	}
+	mark := u.ageHint
+	if mark == nil {
+		mark = tail
+	}
+	if enqueued.Before(u.seen[mark.value].enqueued) {
+		for mark != nil && enqueued.Before(u.seen[mark.value].enqueued) {
+			mark = mark.prev
+		}
+	} else {
+		for mark.next != nil && !enqueued.Before(u.seen[mark.next.value].enqueued) {
+			mark = mark.next
+		}
+	}
+	if mark == nil {
+		u.byAge.insertBefore(n, u.byAge.head)
+	} else {
+		u.byAge.insertAfter(n, mark)
+	}
+	u.ageHint = n
+	return n
+}
+
+func (u *UniqueueUnsafe[T]) removeByAge(e entry[T]) {
+	if u.ageHint == e.age {
+		u.ageHint = nil
+	}
+	u.byAge.unlink(e.age)
+	u.byAge.release(e.age)
+}
+
+// UpdateMetrics reports the current depth and the age of the oldest item to
+// the configured Metrics provider. Both are refreshed automatically on every
+// change; call this periodically to keep the unfinished-work age current
+// while the queue is idle.
+func (u *UniqueueUnsafe[T]) UpdateMetrics() {
+	if u.metrics != nil {
+		u.reportDepth(u.now())
+	}
+}
+
+func (u *UniqueueUnsafe[T]) reportDepth(now time.Time) {
+	u.metrics.Depth(u.queue.Size())
+	u.metrics.UnfinishedWork(u.oldestAge(now))
+}
+
+// Clear removes all items from the queue. The index is reallocated so that
+// memory held at the queue's peak size is released.
+// Observers receive OnDrop for every item, in queue order.
+// Time complexity: O(n) with observers, O(1) otherwise
+func (u *UniqueueUnsafe[T]) Clear() {
+	var dropped []T
+	if len(u.observers) > 0 {
+		dropped = u.queue.PeekN(u.queue.Size())
+	}
+	var zero T
+	u.record(ChangeClear, zero, zero)
+	u.stats.Removes += uint64(u.queue.Size())
+	u.queue.Clear()
+	u.byAge.Clear()
+	u.ageHint = nil
+	u.seen = make(map[T]entry[T], u.sizeHint)
+	u.peak = 0
+	if u.metrics != nil {
+		u.reportDepth(u.now())
+	}
+	for _, item := range dropped {
+		u.notify(eventDrop, item)
+	}
+}
+
+// Retain keeps only the items for which pred returns true, preserving
+// their order, and returns the number of items removed.
+// Observers receive OnDrop for every removed item.
+// Time complexity: O(n)
+func (u *UniqueueUnsafe[T]) Retain(pred func(T) bool) int {
+	var zero T
+	n := u.queue.retain(pred, func(item T) {
+		u.record(ChangeRemove, item, zero)
+		u.removeByAge(u.seen[item])
+		delete(u.seen, item)
+		u.notify(eventDrop, item)
+	})
+	u.stats.Removes += uint64(n)
+	if n > 0 && u.metrics != nil {
+		u.reportDepth(u.now())
+	}
+	u.maybeCompact()
+	return n
+}
+
+// Replace substitutes old with new, keeping its position in the queue.
+// Returns false if old is not in the queue, or if new is already queued
+// (unless it is old itself), since that would break uniqueness.
+// Observers receive OnRemove for old followed by OnPush for new.
+// Time complexity: O(1)
+func (u *UniqueueUnsafe[T]) Replace(old, new T) bool {
+	e, ok := u.seen[old]
+	if !ok {
+		return false
+	}
+	if old == new {
+		return true
+	}
+	if _, exists := u.seen[new]; exists {
+		return false
+	}
+	u.record(ChangeReplace, old, new)
+	e.node.value = new
+	e.age.value = new
+	delete(u.seen, old)
+	u.seen[new] = e
+	u.notify(eventRemove, old)
+	u.notify(eventPush, new)
+	return true
+}
+
+// MoveToFront moves item to the head of the queue so it is popped next.
+// Returns false if the item is not in the queue.
+// Time complexity: O(1)
+func (u *UniqueueUnsafe[T]) MoveToFront(item T) bool {
+	e, ok := u.seen[item]
+	if ok {
+		var zero T
+		u.record(ChangeMoveToFront, item, zero)
+		u.queue.moveToFront(e.node)
+	}
+	return ok
+}
+
+// MoveToBack moves item to the tail of the queue.
+// Returns false if the item is not in the queue.
+// Time complexity: O(1)
+func (u *UniqueueUnsafe[T]) MoveToBack(item T) bool {
+	e, ok := u.seen[item]
+	if ok {
+		var zero T
+		u.record(ChangeMoveToBack, item, zero)
+		u.queue.moveToBack(e.node)
+	}
+	return ok
+}
+
+// MoveBefore moves item directly in front of mark.
+// Returns false if either item is not in the queue. Moving an item
+// relative to itself leaves the queue unchanged.
+// Time complexity: O(1)
+func (u *UniqueueUnsafe[T]) MoveBefore(item, mark T) bool {
+	e, ok := u.seen[item]
+	m, markOK := u.seen[mark]
+	if !ok || !markOK {
+		return false
+	}
+	u.record(ChangeMoveBefore, item, mark)
+	u.queue.moveBefore(e.node, m.node)
+	return true
+}
+
+// MoveAfter moves item directly behind mark.
+// Returns false if either item is not in the queue. Moving an item
+// relative to itself leaves the queue unchanged.
+// Time complexity: O(1)
+func (u *UniqueueUnsafe[T]) MoveAfter(item, mark T) bool {
+	e, ok := u.seen[item]
+	m, markOK := u.seen[mark]
+	if !ok || !markOK {
+		return false
+	}
+	u.record(ChangeMoveAfter, item, mark)
+	u.queue.moveAfter(e.node, m.node)
+	return true
+}
+
+// Peek returns the first item without removing it.
+// Returns the zero value and false if the queue is empty.
+// Time complexity: O(1)
+func (u *UniqueueUnsafe[T]) Peek() (T, bool) {
+	return u.queue.Peek()
+}
+
+// PeekTail returns the last item without removing it.
+// Returns the zero value and false if the queue is empty.
+// Time complexity: O(1)
+func (u *UniqueueUnsafe[T]) PeekTail() (T, bool) {
+	return u.queue.PeekTail()
+}
+
+// PeekN returns up to n items from the head of the queue, in order,
+// without removing them.
+// Time complexity: O(n)
+func (u *UniqueueUnsafe[T]) PeekN(n int) []T {
+	return u.queue.PeekN(n)
+}
+
+// IndexOf returns the zero-based position of item counted from the head.
+// Returns -1 and false if the item is not in the queue.
+// Time complexity: O(i) where i is the returned position
+func (u *UniqueueUnsafe[T]) IndexOf(item T) (int, bool) {
+	e, ok := u.seen[item]
+	if !ok {
+		return -1, false
+	}
+	index := 0
+	for node := e.node.prev; node != nil; node = node.prev {
+		index++
+	}
+	return index, true
+}
+
 // Size returns the number of unique items in the queue.
 // Time complexity: O(1)
 func (u *UniqueueUnsafe[T]) Size() int {
@@ -57,3 +490,15 @@ func (u *UniqueueUnsafe[T]) Contains(item T) bool {
 func (u *UniqueueUnsafe[T]) IsEmpty() bool {
 	return u.Size() == 0
 }
+
+// Options returns the configuration the queue was created with.
+func (u *UniqueueUnsafe[T]) Options() Config {
+	return Config{
+		Observers:   len(u.observers),
+		Metrics:     u.metrics,
+		NodePool:    u.queue.freeMax,
+		SizeHint:    u.sizeHint,
+		AutoCompact: u.autoCompact,
+		ChangeLog:   u.changeLogSize(),
+	}
+}
@@ -1,6 +1,7 @@
 package uniqueue
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -117,6 +118,33 @@ func TestUniqueueUnsafe_PopHead(t *testing.T) {
 	})
 }
 
+func TestUniqueueUnsafe_PushFrontPopTail(t *testing.T) {
+	u := NewUniqueueUnsafe[int]()
+	u.PushBack(2)
+	u.PushFront(1)
+	u.PushFront(2)
+	u.PushBack(3)
+	assertOrder(t, u, []int{1, 2, 3})
+
+	if val, ok := u.PopTail(); !ok || val != 3 {
+		t.Errorf("Expected (3, true), got (%d, %v)", val, ok)
+	}
+	if u.Contains(3) {
+		t.Error("Expected popped item to be forgotten")
+	}
+	assertOrder(t, u, []int{1, 2})
+
+	expected := Stats{Adds: 3, Duplicates: 1, Pops: 1}
+	if got := u.Stats(); got != expected {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+
+	u.Clear()
+	if _, ok := u.PopTail(); ok {
+		t.Error("Expected PopTail on empty queue to return false")
+	}
+}
+
 func TestUniqueueUnsafe_PopHead_CanReAdd(t *testing.T) {
 	u := NewUniqueueUnsafe[string]()
 
@@ -404,3 +432,254 @@ func TestUniqueueUnsafe_ComplexWorkflow(t *testing.T) {
 		t.Errorf("Expected size 5 after re-adding, got %d", u.Size())
 	}
 }
+
+func TestUniqueueUnsafe_Remove(t *testing.T) {
+	u := NewUniqueueUnsafe[int]()
+	u.PushBack(1)
+	u.PushBack(2)
+	u.PushBack(3)
+
+	if !u.Remove(2) {
+		t.Error("Expected Remove(2) to return true")
+	}
+	if u.Remove(2) {
+		t.Error("Expected second Remove(2) to return false")
+	}
+	if u.Contains(2) {
+		t.Error("Expected Contains(2) to return false after removal")
+	}
+	if u.Size() != 2 {
+		t.Errorf("Expected size 2, got %d", u.Size())
+	}
+
+	// Removing head and tail keeps the remaining order intact.
+	u.PushBack(4)
+	u.Remove(1)
+	u.Remove(4)
+	val, ok := u.PopHead()
+	if !ok || val != 3 {
+		t.Errorf("Expected (3, true), got (%d, %v)", val, ok)
+	}
+	if !u.IsEmpty() {
+		t.Error("Expected queue to be empty")
+	}
+
+	// A removed item can be pushed again.
+	u.PushBack(2)
+	if !u.Contains(2) {
+		t.Error("Expected Contains(2) to return true after re-adding")
+	}
+}
+
+func TestUniqueueUnsafe_Stats(t *testing.T) {
+	u := NewUniqueueUnsafe[int]()
+	u.PushBack(1)
+	u.PushBack(2)
+	u.PushBack(1)
+	u.PushBack(1)
+	u.PopHead()
+	u.Remove(2)
+
+	expected := Stats{Adds: 2, Duplicates: 2, Pops: 1, Removes: 1}
+	if got := u.Stats(); got != expected {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestUniqueueUnsafe_Peek(t *testing.T) {
+	u := NewUniqueueUnsafe[string]()
+	if _, ok := u.Peek(); ok {
+		t.Error("Expected ok=false for empty queue")
+	}
+
+	u.PushBack("a")
+	u.PushBack("b")
+	u.PushBack("c")
+
+	if val, ok := u.Peek(); !ok || val != "a" {
+		t.Errorf("Expected ('a', true), got (%s, %v)", val, ok)
+	}
+	if val, ok := u.PeekTail(); !ok || val != "c" {
+		t.Errorf("Expected ('c', true), got (%s, %v)", val, ok)
+	}
+	if got := u.PeekN(2); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %v", got)
+	}
+	if u.Size() != 3 {
+		t.Errorf("Expected size 3, got %d", u.Size())
+	}
+}
+
+func TestUniqueueUnsafe_IndexOf(t *testing.T) {
+	u := NewUniqueueUnsafe[string]()
+	u.PushBack("a")
+	u.PushBack("b")
+	u.PushBack("c")
+
+	for expected, item := range []string{"a", "b", "c"} {
+		if index, ok := u.IndexOf(item); !ok || index != expected {
+			t.Errorf("IndexOf(%s): expected (%d, true), got (%d, %v)", item, expected, index, ok)
+		}
+	}
+	if index, ok := u.IndexOf("missing"); ok || index != -1 {
+		t.Errorf("Expected (-1, false), got (%d, %v)", index, ok)
+	}
+
+	u.PopHead()
+	if index, _ := u.IndexOf("c"); index != 1 {
+		t.Errorf("Expected index 1 after pop, got %d", index)
+	}
+}
+
+// assertOrder checks the queue contents walking both forward and backward,
+// so broken prev links are caught as well as broken next links.
+func assertOrder[T comparable](t *testing.T, u *UniqueueUnsafe[T], expected []T) {
+	t.Helper()
+	if got := u.PeekN(u.Size()); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	var backward []T
+	for node := u.queue.tail; node != nil; node = node.prev {
+		backward = append([]T{node.value}, backward...)
+	}
+	if len(expected) == 0 {
+		expected = nil
+	}
+	if !reflect.DeepEqual(backward, expected) {
+		t.Errorf("Expected %v walking backward, got %v", expected, backward)
+	}
+}
+
+func TestUniqueueUnsafe_Move(t *testing.T) {
+	newQueue := func() *UniqueueUnsafe[int] {
+		u := NewUniqueueUnsafe[int]()
+		for i := 1; i <= 4; i++ {
+			u.PushBack(i)
+		}
+		return u
+	}
+
+	tests := []struct {
+		name     string
+		move     func(u *UniqueueUnsafe[int]) bool
+		ok       bool
+		expected []int
+	}{
+		{"to front", func(u *UniqueueUnsafe[int]) bool { return u.MoveToFront(3) }, true, []int{3, 1, 2, 4}},
+		{"head to front", func(u *UniqueueUnsafe[int]) bool { return u.MoveToFront(1) }, true, []int{1, 2, 3, 4}},
+		{"tail to front", func(u *UniqueueUnsafe[int]) bool { return u.MoveToFront(4) }, true, []int{4, 1, 2, 3}},
+		{"to back", func(u *UniqueueUnsafe[int]) bool { return u.MoveToBack(1) }, true, []int{2, 3, 4, 1}},
+		{"tail to back", func(u *UniqueueUnsafe[int]) bool { return u.MoveToBack(4) }, true, []int{1, 2, 3, 4}},
+		{"before", func(u *UniqueueUnsafe[int]) bool { return u.MoveBefore(4, 2) }, true, []int{1, 4, 2, 3}},
+		{"before head", func(u *UniqueueUnsafe[int]) bool { return u.MoveBefore(3, 1) }, true, []int{3, 1, 2, 4}},
+		{"before next", func(u *UniqueueUnsafe[int]) bool { return u.MoveBefore(2, 3) }, true, []int{1, 2, 3, 4}},
+		{"after", func(u *UniqueueUnsafe[int]) bool { return u.MoveAfter(1, 3) }, true, []int{2, 3, 1, 4}},
+		{"after tail", func(u *UniqueueUnsafe[int]) bool { return u.MoveAfter(2, 4) }, true, []int{1, 3, 4, 2}},
+		{"after self", func(u *UniqueueUnsafe[int]) bool { return u.MoveAfter(2, 2) }, true, []int{1, 2, 3, 4}},
+		{"missing item", func(u *UniqueueUnsafe[int]) bool { return u.MoveToFront(9) }, false, []int{1, 2, 3, 4}},
+		{"missing mark", func(u *UniqueueUnsafe[int]) bool { return u.MoveBefore(1, 9) }, false, []int{1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newQueue()
+			if ok := tt.move(u); ok != tt.ok {
+				t.Errorf("Expected %v, got %v", tt.ok, ok)
+			}
+			assertOrder(t, u, tt.expected)
+			if u.Size() != 4 {
+				t.Errorf("Expected size 4, got %d", u.Size())
+			}
+		})
+	}
+}
+
+func TestUniqueueUnsafe_Clear(t *testing.T) {
+	obs := &recordingObserver{}
+	u := NewUniqueueUnsafe[int](WithObserver[int](obs))
+	u.PushBack(1)
+	u.PushBack(2)
+
+	u.Clear()
+	assertOrder(t, u, []int{})
+	if u.Contains(1) || u.Contains(2) {
+		t.Error("Expected Contains to return false after Clear")
+	}
+	expected := []string{"push:1", "push:2", "drop:1", "drop:2"}
+	if got := obs.Events(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected events %v, got %v", expected, got)
+	}
+
+	u.PushBack(1)
+	if u.Size() != 1 {
+		t.Errorf("Expected cleared item to be pushable again, got size %d", u.Size())
+	}
+}
+
+func TestUniqueueUnsafe_Retain(t *testing.T) {
+	u := NewUniqueueUnsafe[int]()
+	for i := 1; i <= 5; i++ {
+		u.PushBack(i)
+	}
+
+	if removed := u.Retain(func(v int) bool { return v != 1 && v != 3 && v != 5 }); removed != 3 {
+		t.Errorf("Expected 3 removed, got %d", removed)
+	}
+	assertOrder(t, u, []int{2, 4})
+	if u.Contains(3) {
+		t.Error("Expected removed item to leave the index")
+	}
+	u.PushBack(3)
+	assertOrder(t, u, []int{2, 4, 3})
+}
+
+func TestUniqueueUnsafe_Replace(t *testing.T) {
+	u := NewUniqueueUnsafe[string]()
+	u.PushBack("a")
+	u.PushBack("b")
+	u.PushBack("c")
+
+	if !u.Replace("b", "x") {
+		t.Error("Expected Replace to return true")
+	}
+	assertOrder(t, u, []string{"a", "x", "c"})
+	if u.Contains("b") || !u.Contains("x") {
+		t.Error("Expected index to follow the replacement")
+	}
+	if index, _ := u.IndexOf("x"); index != 1 {
+		t.Errorf("Expected x at index 1, got %d", index)
+	}
+
+	if u.Replace("a", "c") {
+		t.Error("Expected Replace to refuse creating a duplicate")
+	}
+	if u.Replace("missing", "y") {
+		t.Error("Expected Replace to return false for missing item")
+	}
+	if !u.Replace("a", "a") {
+		t.Error("Expected replacing an item with itself to succeed")
+	}
+	assertOrder(t, u, []string{"a", "x", "c"})
+}
+
+func TestUniqueueUnsafe_NodePool_Allocs(t *testing.T) {
+	u := NewUniqueueUnsafe[int](WithNodePool(16))
+	for i := 0; i < 16; i++ {
+		u.PushBack(i)
+	}
+	for i := 0; i < 16; i++ {
+		u.PopHead()
+	}
+
+	allocs := testing.AllocsPerRun(1000, func() {
+		for i := 0; i < 16; i++ {
+			u.PushBack(i)
+		}
+		u.Remove(3)
+		for i := 0; i < 16; i++ {
+			u.PopHead()
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Expected steady-state push/pop not to allocate, got %v allocs per run", allocs)
+	}
+}
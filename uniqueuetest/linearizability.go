@@ -0,0 +1,360 @@
+package uniqueuetest
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math/bits"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/realfatcat/uniqueue"
+)
+
+// OpKind identifies the method called by an Operation.
+type OpKind int
+
+const (
+	// OpPushBack is a call of PushBack(Item).
+	OpPushBack OpKind = iota
+	// OpPopHead is a call of PopHead, or a successful PopHeadWait, that
+	// returned (Item, OK).
+	OpPopHead
+	// OpContains is a call of Contains(Item) that returned OK.
+	OpContains
+	// OpSize is a call of Size that returned Size.
+	OpSize
+)
+
+// Operation is one completed call in a concurrent history.
+type Operation[T comparable] struct {
+	// Client identifies the goroutine that made the call.
+	Client int
+	Kind   OpKind
+	// Item is the argument of PushBack and Contains, and the item
+	// returned by PopHead.
+	Item T
+	// OK is the boolean result of PopHead and Contains.
+	OK   bool
+	Size int
+	// Call and Return are when the call was made and when it returned,
+	// measured from the start of the recording.
+	Call, Return time.Duration
+}
+
+func (op Operation[T]) String() string {
+	var call string
+	switch op.Kind {
+	case OpPushBack:
+		call = fmt.Sprintf("PushBack(%v)", op.Item)
+	case OpPopHead:
+		call = fmt.Sprintf("PopHead() = (%v, %v)", op.Item, op.OK)
+	case OpContains:
+		call = fmt.Sprintf("Contains(%v) = %v", op.Item, op.OK)
+	case OpSize:
+		call = fmt.Sprintf("Size() = %d", op.Size)
+	default:
+		call = fmt.Sprintf("OpKind(%d)", int(op.Kind))
+	}
+	return fmt.Sprintf("client %d: %s [%v, %v]", op.Client, call, op.Call, op.Return)
+}
+
+// FormatHistory returns history sorted by call time, one operation per
+// line, for failure messages.
+func FormatHistory[T comparable](history []Operation[T]) string {
+	sorted := slices.Clone(history)
+	slices.SortStableFunc(sorted, func(a, b Operation[T]) int { return cmp.Compare(a.Call, b.Call) })
+	var sb strings.Builder
+	for _, op := range sorted {
+		sb.WriteString(op.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Recorder calls a queue on behalf of concurrent clients and records every
+// call with its invoke and return times. All methods are safe for
+// concurrent use if the queue is.
+type Recorder[T comparable] struct {
+	q     uniqueue.Interface[T]
+	start time.Time
+
+	mu      sync.Mutex
+	history []Operation[T]
+}
+
+// NewRecorder returns a recorder with an empty history for q.
+func NewRecorder[T comparable](q uniqueue.Interface[T]) *Recorder[T] {
+	return &Recorder[T]{q: q, start: time.Now()}
+}
+
+func (r *Recorder[T]) record(op Operation[T], call time.Duration) {
+	op.Call = call
+	op.Return = time.Since(r.start)
+	r.mu.Lock()
+	r.history = append(r.history, op)
+	r.mu.Unlock()
+}
+
+// PushBack calls PushBack on the queue.
+func (r *Recorder[T]) PushBack(client int, item T) {
+	call := time.Since(r.start)
+	r.q.PushBack(item)
+	r.record(Operation[T]{Client: client, Kind: OpPushBack, Item: item}, call)
+}
+
+// PopHead calls PopHead on the queue.
+func (r *Recorder[T]) PopHead(client int) (T, bool) {
+	call := time.Since(r.start)
+	item, ok := r.q.PopHead()
+	r.record(Operation[T]{Client: client, Kind: OpPopHead, Item: item, OK: ok}, call)
+	return item, ok
+}
+
+// PopHeadWait calls PopHeadWait on the queue, which must implement
+// uniqueue.BlockingInterface. A call that fails has no effect on the queue
+// and is not recorded.
+func (r *Recorder[T]) PopHeadWait(ctx context.Context, client int) (T, error) {
+	call := time.Since(r.start)
+	item, err := r.q.(uniqueue.BlockingInterface[T]).PopHeadWait(ctx)
+	if err == nil {
+		r.record(Operation[T]{Client: client, Kind: OpPopHead, Item: item, OK: true}, call)
+	}
+	return item, err
+}
+
+// Contains calls Contains on the queue.
+func (r *Recorder[T]) Contains(client int, item T) bool {
+	call := time.Since(r.start)
+	ok := r.q.Contains(item)
+	r.record(Operation[T]{Client: client, Kind: OpContains, Item: item, OK: ok}, call)
+	return ok
+}
+
+// Size calls Size on the queue.
+func (r *Recorder[T]) Size(client int) int {
+	call := time.Since(r.start)
+	n := r.q.Size()
+	r.record(Operation[T]{Client: client, Kind: OpSize, Size: n}, call)
+	return n
+}
+
+// History returns the operations recorded so far in completion order.
+func (r *Recorder[T]) History() []Operation[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.history)
+}
+
+// Model is a sequential specification that histories are checked against.
+type Model[T comparable, S any] struct {
+	// Init returns the state of a new queue.
+	Init func() S
+	// Step applies op to state and reports whether the results recorded in
+	// op are possible. It must not modify state.
+	Step func(state S, op Operation[T]) (S, bool)
+	// Equal reports whether two states are the same.
+	Equal func(a, b S) bool
+}
+
+// QueueModel returns the specification of a FIFO queue whose state is its
+// items from head to tail. If unique is true, PushBack ignores items that
+// are already queued.
+func QueueModel[T comparable](unique bool) Model[T, []T] {
+	return Model[T, []T]{
+		Init: func() []T { return nil },
+		Step: func(state []T, op Operation[T]) ([]T, bool) {
+			switch op.Kind {
+			case OpPushBack:
+				if unique && slices.Contains(state, op.Item) {
+					return state, true
+				}
+				// Copy so that states on other search paths stay intact.
+				return slices.Concat(state, []T{op.Item}), true
+			case OpPopHead:
+				if !op.OK {
+					return state, len(state) == 0
+				}
+				if len(state) == 0 || state[0] != op.Item {
+					return state, false
+				}
+				return state[1:], true
+			case OpContains:
+				return state, slices.Contains(state, op.Item) == op.OK
+			case OpSize:
+				return state, len(state) == op.Size
+			}
+			return state, false
+		},
+		Equal: slices.Equal[[]T],
+	}
+}
+
+// CheckLinearizable reports whether history is linearizable with respect
+// to m: whether every operation can be assigned a point between its call
+// and return such that executing the operations in that order on m gives
+// the recorded results.
+//
+// The check is a depth-first search over the operations that may go next,
+// as described by Wing and Gong, pruned by remembering every combination
+// of linearized operations and resulting state already explored (Lowe).
+// Its cost grows exponentially with the number of overlapping operations,
+// so keep histories to a few hundred operations.
+func CheckLinearizable[T comparable, S any](m Model[T, S], history []Operation[T]) bool {
+	head := buildEntries(history)
+	linearized := make(bitset, (len(history)+63)/64)
+	cache := make(map[uint64][]cacheEntry[S])
+	type frame struct {
+		entry *entry
+		state S
+	}
+	var stack []frame
+
+	state := m.Init()
+	e := head.next
+	for head.next != nil {
+		if e.match == nil {
+			// e returns before any remaining call could be linearized
+			// next; undo the last choice.
+			if len(stack) == 0 {
+				return false
+			}
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			state = top.state
+			linearized.clear(top.entry.op)
+			top.entry.unlift()
+			e = top.entry.next
+			continue
+		}
+
+		next, ok := m.Step(state, history[e.op])
+		if ok {
+			linearized.set(e.op)
+			if cacheAdd(cache, m.Equal, linearized, next) {
+				stack = append(stack, frame{entry: e, state: state})
+				state = next
+				e.lift()
+				e = head.next
+				continue
+			}
+			linearized.clear(e.op)
+		}
+		e = e.next
+	}
+	return true
+}
+
+// entry is a call or return event in a doubly linked list sorted by time.
+// match links a call to its return and is nil for returns.
+type entry struct {
+	op         int
+	match      *entry
+	prev, next *entry
+}
+
+// buildEntries returns the head sentinel of the event list of history.
+// A call and a return at the same instant are treated as overlapping.
+func buildEntries[T comparable](history []Operation[T]) *entry {
+	type event struct {
+		op   int
+		ret  bool
+		time time.Duration
+	}
+	events := make([]event, 0, 2*len(history))
+	for i, op := range history {
+		events = append(events, event{op: i, time: op.Call}, event{op: i, ret: true, time: op.Return})
+	}
+	slices.SortStableFunc(events, func(a, b event) int {
+		if a.time != b.time {
+			return cmp.Compare(a.time, b.time)
+		}
+		// Calls first, so that they overlap with returns at that instant.
+		if a.ret == b.ret {
+			return 0
+		}
+		if b.ret {
+			return -1
+		}
+		return 1
+	})
+
+	head := &entry{}
+	last := head
+	returns := make([]*entry, len(history))
+	calls := make([]*entry, len(history))
+	for _, ev := range events {
+		e := &entry{op: ev.op, prev: last}
+		last.next = e
+		last = e
+		if ev.ret {
+			returns[ev.op] = e
+		} else {
+			calls[ev.op] = e
+		}
+	}
+	for i, call := range calls {
+		call.match = returns[i]
+	}
+	return head
+}
+
+// lift removes the call e and its return from the list.
+func (e *entry) lift() {
+	e.prev.next = e.next
+	if e.next != nil {
+		e.next.prev = e.prev
+	}
+	r := e.match
+	r.prev.next = r.next
+	if r.next != nil {
+		r.next.prev = r.prev
+	}
+}
+
+// unlift puts back the call e and its return removed by lift.
+func (e *entry) unlift() {
+	r := e.match
+	r.prev.next = r
+	if r.next != nil {
+		r.next.prev = r
+	}
+	e.prev.next = e
+	if e.next != nil {
+		e.next.prev = e
+	}
+}
+
+type bitset []uint64
+
+func (b bitset) set(i int)   { b[i/64] |= 1 << (i % 64) }
+func (b bitset) clear(i int) { b[i/64] &^= 1 << (i % 64) }
+
+func (b bitset) hash() uint64 {
+	h := uint64(len(b))
+	for _, w := range b {
+		h = bits.RotateLeft64(h, 17) ^ w*0x9e3779b97f4a7c15
+	}
+	return h
+}
+
+type cacheEntry[S any] struct {
+	linearized bitset
+	state      S
+}
+
+// cacheAdd remembers the pair of linearized and state, and returns false
+// if it was already explored.
+func cacheAdd[S any](cache map[uint64][]cacheEntry[S], equal func(a, b S) bool, linearized bitset, state S) bool {
+	h := linearized.hash()
+	for _, c := range cache[h] {
+		if slices.Equal(c.linearized, linearized) && equal(c.state, state) {
+			return false
+		}
+	}
+	cache[h] = append(cache[h], cacheEntry[S]{linearized: slices.Clone(linearized), state: state})
+	return true
+}
@@ -0,0 +1,155 @@
+package uniqueuetest_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/realfatcat/uniqueue"
+	"github.com/realfatcat/uniqueue/uniqueuetest"
+)
+
+// op builds an operation that runs from call to ret, in microseconds.
+func op(client int, kind uniqueuetest.OpKind, item string, ok bool, call, ret int) uniqueuetest.Operation[string] {
+	return uniqueuetest.Operation[string]{
+		Client: client,
+		Kind:   kind,
+		Item:   item,
+		OK:     ok,
+		Call:   time.Duration(call) * time.Microsecond,
+		Return: time.Duration(ret) * time.Microsecond,
+	}
+}
+
+func TestCheckLinearizable(t *testing.T) {
+	tests := []struct {
+		name     string
+		unique   bool
+		history  []uniqueuetest.Operation[string]
+		expected bool
+	}{
+		{"empty history", true, nil, true},
+		{
+			"sequential",
+			true,
+			[]uniqueuetest.Operation[string]{
+				op(0, uniqueuetest.OpPushBack, "a", false, 0, 1),
+				op(0, uniqueuetest.OpPushBack, "b", false, 2, 3),
+				op(0, uniqueuetest.OpPopHead, "a", true, 4, 5),
+				op(0, uniqueuetest.OpContains, "b", true, 6, 7),
+				{Kind: uniqueuetest.OpSize, Size: 1, Call: 8 * time.Microsecond, Return: 9 * time.Microsecond},
+			},
+			true,
+		},
+		{
+			"FIFO violated",
+			true,
+			[]uniqueuetest.Operation[string]{
+				op(0, uniqueuetest.OpPushBack, "a", false, 0, 1),
+				op(0, uniqueuetest.OpPushBack, "b", false, 2, 3),
+				op(1, uniqueuetest.OpPopHead, "b", true, 4, 5),
+			},
+			false,
+		},
+		{
+			"overlapping pushes in either order",
+			true,
+			[]uniqueuetest.Operation[string]{
+				op(0, uniqueuetest.OpPushBack, "a", false, 0, 10),
+				op(1, uniqueuetest.OpPushBack, "b", false, 1, 9),
+				op(2, uniqueuetest.OpPopHead, "b", true, 11, 12),
+				op(2, uniqueuetest.OpPopHead, "a", true, 13, 14),
+			},
+			true,
+		},
+		{
+			"item popped twice",
+			true,
+			[]uniqueuetest.Operation[string]{
+				op(0, uniqueuetest.OpPushBack, "a", false, 0, 1),
+				op(1, uniqueuetest.OpPopHead, "a", true, 2, 5),
+				op(2, uniqueuetest.OpPopHead, "a", true, 3, 6),
+			},
+			false,
+		},
+		{
+			"pop overlapping the push",
+			true,
+			[]uniqueuetest.Operation[string]{
+				op(0, uniqueuetest.OpPushBack, "a", false, 0, 5),
+				op(1, uniqueuetest.OpPopHead, "a", true, 1, 2),
+				op(1, uniqueuetest.OpPopHead, "", false, 3, 4),
+			},
+			true,
+		},
+		{
+			"stale contains",
+			true,
+			[]uniqueuetest.Operation[string]{
+				op(0, uniqueuetest.OpPushBack, "a", false, 0, 1),
+				op(1, uniqueuetest.OpContains, "a", false, 2, 3),
+			},
+			false,
+		},
+		{
+			"duplicate ignored",
+			true,
+			[]uniqueuetest.Operation[string]{
+				op(0, uniqueuetest.OpPushBack, "a", false, 0, 1),
+				op(1, uniqueuetest.OpPushBack, "a", false, 2, 3),
+				op(0, uniqueuetest.OpPopHead, "a", true, 4, 5),
+				op(0, uniqueuetest.OpPopHead, "", false, 6, 7),
+			},
+			true,
+		},
+		{
+			"duplicate kept",
+			false,
+			[]uniqueuetest.Operation[string]{
+				op(0, uniqueuetest.OpPushBack, "a", false, 0, 1),
+				op(1, uniqueuetest.OpPushBack, "a", false, 2, 3),
+				op(0, uniqueuetest.OpPopHead, "a", true, 4, 5),
+				op(0, uniqueuetest.OpPopHead, "", false, 6, 7),
+			},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uniqueuetest.CheckLinearizable(uniqueuetest.QueueModel[string](tt.unique), tt.history); got != tt.expected {
+				t.Errorf("Expected %v, got %v for:\n%s", tt.expected, got, uniqueuetest.FormatHistory(tt.history))
+			}
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	r := uniqueuetest.NewRecorder[string](uniqueue.NewUniqueue[string]())
+	r.PushBack(0, "a")
+	r.PushBack(1, "a")
+	if !r.Contains(0, "a") || r.Size(1) != 1 {
+		t.Error("Expected the recorder to pass calls through")
+	}
+	if item, err := r.PopHeadWait(t.Context(), 1); err != nil || item != "a" {
+		t.Errorf("Expected (a, nil), got (%v, %v)", item, err)
+	}
+	if _, ok := r.PopHead(0); ok {
+		t.Error("Expected the queue to be empty")
+	}
+
+	history := r.History()
+	if len(history) != 6 {
+		t.Fatalf("Expected 6 operations, got %d", len(history))
+	}
+	for i, op := range history {
+		if op.Return < op.Call || (i > 0 && op.Call < history[i-1].Return) {
+			t.Errorf("Expected sequential calls to have ordered times, got %v", op)
+		}
+	}
+	if !uniqueuetest.CheckLinearizable(uniqueuetest.QueueModel[string](true), history) {
+		t.Errorf("Expected a sequential history to be linearizable:\n%s", uniqueuetest.FormatHistory(history))
+	}
+	if s := uniqueuetest.FormatHistory(history); !strings.Contains(s, "client 1: PopHead() = (a, true)") {
+		t.Errorf("Expected the formatted history to list the pop, got:\n%s", s)
+	}
+}
@@ -0,0 +1,270 @@
+// Package uniqueuetest provides a conformance test suite for
+// implementations of uniqueue.Interface and uniqueue.BlockingInterface,
+// and a linearizability checker for concurrent ones.
+//
+// Call Suite.Run from a test in the implementation's package:
+//
+//	func TestConformance(t *testing.T) {
+//		uniqueuetest.Suite[string]{
+//			New:    func() uniqueue.Interface[string] { return NewMyQueue() },
+//			Items:  []string{"a", "b", "c"},
+//			Unique: true,
+//		}.Run(t)
+//	}
+package uniqueuetest
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/realfatcat/uniqueue"
+)
+
+// Suite describes the implementation under test.
+type Suite[T comparable] struct {
+	// New returns an empty queue. It is called once per subtest.
+	New func() uniqueue.Interface[T]
+	// Items are distinct sample values; at least three are required.
+	Items []T
+	// Unique reports whether PushBack ignores items already in the queue.
+	Unique bool
+}
+
+// Run runs the suite as subtests of t. If the queues returned by New
+// implement uniqueue.BlockingInterface, the blocking and concurrency tests,
+// including a linearizability check, are run as well.
+func (s Suite[T]) Run(t *testing.T) {
+	t.Helper()
+	if s.New == nil {
+		t.Fatal("uniqueuetest: Suite.New is nil")
+	}
+	if len(s.Items) < 3 {
+		t.Fatalf("uniqueuetest: Suite.Items needs at least 3 items, got %d", len(s.Items))
+	}
+
+	t.Run("Empty", s.testEmpty)
+	t.Run("FIFOOrder", s.testFIFOOrder)
+	t.Run("Contains", s.testContains)
+	t.Run("Size", s.testSize)
+	t.Run("Duplicates", s.testDuplicates)
+
+	if _, ok := s.New().(uniqueue.BlockingInterface[T]); !ok {
+		return
+	}
+	t.Run("PopHeadWait", s.testPopHeadWait)
+	t.Run("PopHeadWaitCancel", s.testPopHeadWaitCancel)
+	t.Run("Concurrent", s.testConcurrent)
+	t.Run("Linearizable", s.testLinearizable)
+}
+
+func (s Suite[T]) testEmpty(t *testing.T) {
+	q := s.New()
+	if q.Size() != 0 || !q.IsEmpty() {
+		t.Errorf("Expected new queue to be empty, got size %d", q.Size())
+	}
+	if val, ok := q.PopHead(); ok {
+		t.Errorf("Expected PopHead on empty queue to return false, got %v", val)
+	}
+	if q.Contains(s.Items[0]) {
+		t.Errorf("Expected empty queue not to contain %v", s.Items[0])
+	}
+}
+
+func (s Suite[T]) testFIFOOrder(t *testing.T) {
+	q := s.New()
+	for _, item := range s.Items {
+		q.PushBack(item)
+	}
+	for _, expected := range s.Items {
+		if val, ok := q.PopHead(); !ok || val != expected {
+			t.Errorf("Expected (%v, true), got (%v, %v)", expected, val, ok)
+		}
+	}
+	if _, ok := q.PopHead(); ok {
+		t.Error("Expected queue to be empty after popping every item")
+	}
+}
+
+func (s Suite[T]) testContains(t *testing.T) {
+	q := s.New()
+	a, b := s.Items[0], s.Items[1]
+	q.PushBack(a)
+	if !q.Contains(a) {
+		t.Errorf("Expected queue to contain %v after PushBack", a)
+	}
+	if q.Contains(b) {
+		t.Errorf("Expected queue not to contain %v", b)
+	}
+	q.PopHead()
+	if q.Contains(a) {
+		t.Errorf("Expected queue not to contain %v after PopHead", a)
+	}
+}
+
+func (s Suite[T]) testSize(t *testing.T) {
+	q := s.New()
+	for i, item := range s.Items {
+		q.PushBack(item)
+		if q.Size() != i+1 || q.IsEmpty() {
+			t.Errorf("Expected size %d, got %d (IsEmpty %v)", i+1, q.Size(), q.IsEmpty())
+		}
+	}
+	for i := len(s.Items) - 1; i >= 0; i-- {
+		q.PopHead()
+		if q.Size() != i || q.IsEmpty() != (i == 0) {
+			t.Errorf("Expected size %d, got %d (IsEmpty %v)", i, q.Size(), q.IsEmpty())
+		}
+	}
+}
+
+func (s Suite[T]) testDuplicates(t *testing.T) {
+	q := s.New()
+	a, b := s.Items[0], s.Items[1]
+	q.PushBack(a)
+	q.PushBack(b)
+	q.PushBack(a)
+
+	if !s.Unique {
+		if q.Size() != 3 {
+			t.Errorf("Expected duplicates to be kept, got size %d", q.Size())
+		}
+		return
+	}
+
+	if q.Size() != 2 {
+		t.Errorf("Expected duplicate to be ignored, got size %d", q.Size())
+	}
+	if val, _ := q.PopHead(); val != a {
+		t.Errorf("Expected duplicate push to keep the original position, got %v first", val)
+	}
+	q.PushBack(a)
+	if q.Size() != 2 || !q.Contains(a) {
+		t.Errorf("Expected a popped item to be accepted again, got size %d", q.Size())
+	}
+}
+
+func (s Suite[T]) testPopHeadWait(t *testing.T) {
+	q := s.New().(uniqueue.BlockingInterface[T])
+	a, b := s.Items[0], s.Items[1]
+
+	q.PushBack(a)
+	if val, err := q.PopHeadWait(context.Background()); err != nil || val != a {
+		t.Errorf("Expected (%v, nil), got (%v, %v)", a, val, err)
+	}
+
+	done := make(chan T, 1)
+	go func() {
+		val, _ := q.PopHeadWait(context.Background())
+		done <- val
+	}()
+	select {
+	case val := <-done:
+		t.Fatalf("Expected PopHeadWait to block on an empty queue, got %v", val)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	q.PushBack(b)
+	select {
+	case val := <-done:
+		if val != b {
+			t.Errorf("Expected %v, got %v", b, val)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PopHeadWait did not return after PushBack")
+	}
+}
+
+func (s Suite[T]) testPopHeadWaitCancel(t *testing.T) {
+	q := s.New().(uniqueue.BlockingInterface[T])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.PopHeadWait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := q.PopHeadWait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Canceled, got %v", err)
+	}
+}
+
+// testConcurrent pushes every item from its own goroutine while as many
+// consumers wait for them, and checks that each item is delivered once.
+func (s Suite[T]) testConcurrent(t *testing.T) {
+	q := s.New().(uniqueue.BlockingInterface[T])
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	popped := make(chan T, len(s.Items))
+	for range s.Items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if val, err := q.PopHeadWait(ctx); err == nil {
+				popped <- val
+			}
+		}()
+	}
+	for _, item := range s.Items {
+		go q.PushBack(item)
+	}
+	wg.Wait()
+	close(popped)
+
+	counts := make(map[T]int)
+	for val := range popped {
+		counts[val]++
+	}
+	for _, item := range s.Items {
+		if counts[item] != 1 {
+			t.Errorf("Expected %v to be delivered once, got %d", item, counts[item])
+		}
+	}
+	if !q.IsEmpty() {
+		t.Errorf("Expected queue to be empty, got size %d", q.Size())
+	}
+}
+
+// testLinearizable records random operations of several goroutines on
+// three items and checks each history against QueueModel.
+func (s Suite[T]) testLinearizable(t *testing.T) {
+	const rounds, clients, opsPerClient = 20, 4, 15
+	items := s.Items[:3]
+	for round := range rounds {
+		r := NewRecorder(s.New())
+		var wg sync.WaitGroup
+		for client := range clients {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rng := rand.New(rand.NewPCG(uint64(round), uint64(client)))
+				for range opsPerClient {
+					item := items[rng.IntN(len(items))]
+					switch rng.IntN(7) {
+					case 0, 1, 2:
+						r.PushBack(client, item)
+					case 3, 4:
+						r.PopHead(client)
+					case 5:
+						r.Contains(client, item)
+					case 6:
+						r.Size(client)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		history := r.History()
+		if !CheckLinearizable(QueueModel[T](s.Unique), history) {
+			t.Fatalf("Expected a linearizable history in round %d, got:\n%s", round, FormatHistory(history))
+		}
+	}
+}
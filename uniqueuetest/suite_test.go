@@ -0,0 +1,31 @@
+package uniqueuetest_test
+
+import (
+	"testing"
+
+	"github.com/realfatcat/uniqueue"
+	"github.com/realfatcat/uniqueue/uniqueuetest"
+)
+
+func TestQueue(t *testing.T) {
+	uniqueuetest.Suite[int]{
+		New:   func() uniqueue.Interface[int] { return uniqueue.NewQueue[int]() },
+		Items: []int{1, 2, 3, 4, 5},
+	}.Run(t)
+}
+
+func TestUniqueueUnsafe(t *testing.T) {
+	uniqueuetest.Suite[string]{
+		New:    func() uniqueue.Interface[string] { return uniqueue.NewUniqueueUnsafe[string]() },
+		Items:  []string{"a", "b", "c", "d"},
+		Unique: true,
+	}.Run(t)
+}
+
+func TestUniqueue(t *testing.T) {
+	uniqueuetest.Suite[int]{
+		New:    func() uniqueue.Interface[int] { return uniqueue.NewUniqueue[int]() },
+		Items:  []int{10, 20, 30, 40, 50, 60, 70, 80},
+		Unique: true,
+	}.Run(t)
+}